@@ -0,0 +1,129 @@
+package pomodoro
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// HistoryBackups turns on the daily backup of the history log that
+// logSession takes before the first record it writes each day.
+var HistoryBackups = true
+
+func backupPath() (string, error) {
+	path, err := historyPath()
+	if err != nil {
+		return "", err
+	}
+
+	return path + ".bak", nil
+}
+
+// backupHistory copies the history log to its backup, unless the backup was
+// already taken on the day of now.
+func backupHistory(now time.Time) error {
+	path, err := historyPath()
+	if err != nil {
+		return err
+	}
+	bak, err := backupPath()
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(bak); err == nil && sameDay(info.ModTime(), now) {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// A log that cannot be read must not overwrite the last good backup.
+	if !historyReadable(data) {
+		return nil
+	}
+	return copyFile(bak, data)
+}
+
+// historyReadable reports whether data holds a usable history log: empty,
+// or with at least one record that can be read back.
+func historyReadable(data []byte) bool {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return true
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var record sessionRecord
+		if json.Unmarshal(scanner.Bytes(), &record) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// damagedHistory reports whether the history log cannot be read while its
+// backup can, returning when the backup was taken.
+func damagedHistory() (time.Time, bool) {
+	path, err := historyPath()
+	if err != nil {
+		return time.Time{}, false
+	}
+	bak, err := backupPath()
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) || (err == nil && historyReadable(data)) {
+		return time.Time{}, false
+	}
+	backup, err := os.ReadFile(bak)
+	if err != nil || !historyReadable(backup) {
+		return time.Time{}, false
+	}
+	info, err := os.Stat(bak)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return info.ModTime(), true
+}
+
+// restoreHistory replaces the history log with its backup. The lifetime
+// cache counted the damaged log, so it is dropped to be rebuilt.
+func restoreHistory() error {
+	path, err := historyPath()
+	if err != nil {
+		return err
+	}
+	bak, err := backupPath()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(bak)
+	if err != nil {
+		return err
+	}
+	if err := copyFile(path, data); err != nil {
+		return err
+	}
+
+	if cachePath, err := lifetimeCachePath(); err == nil {
+		os.Remove(cachePath)
+	}
+	return nil
+}
+
+// copyFile writes data to path through a temporary file, so path is never
+// left half written.
+func copyFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
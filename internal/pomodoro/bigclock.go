@@ -0,0 +1,40 @@
+package pomodoro
+
+import (
+	"strings"
+	"time"
+)
+
+// bigGlyphs is a block font for the characters of a clock, five rows high.
+var bigGlyphs = map[rune][5]string{
+	'0': {"███", "█ █", "█ █", "█ █", "███"},
+	'1': {" ██", "  █", "  █", "  █", "  █"},
+	'2': {"███", "  █", "███", "█  ", "███"},
+	'3': {"███", "  █", "███", "  █", "███"},
+	'4': {"█ █", "█ █", "███", "  █", "  █"},
+	'5': {"███", "█  ", "███", "  █", "███"},
+	'6': {"███", "█  ", "███", "█ █", "███"},
+	'7': {"███", "  █", "  █", "  █", "  █"},
+	'8': {"███", "█ █", "███", "█ █", "███"},
+	'9': {"███", "█ █", "███", "  █", "███"},
+	':': {" ", "█", " ", "█", " "},
+}
+
+// bigClock renders d as mm:ss, or h:mm:ss from an hour up, in bigGlyphs.
+func bigClock(d time.Duration) string {
+	var rows [5]string
+	for i, r := range formatDuration(d) {
+		glyph := bigGlyphs[r]
+		for row := range rows {
+			if i > 0 {
+				rows[row] += " "
+			}
+			rows[row] += glyph[row]
+		}
+	}
+
+	// The window trims trailing spaces off each line before centering it,
+	// which would shear the digits apart, so pad them with blank braille
+	// cells instead.
+	return strings.ReplaceAll(strings.Join(rows[:], "\n"), " ", "\u2800")
+}
@@ -0,0 +1,784 @@
+package pomodoro
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SessionKind tells the pomodoro cycle what a session is for.
+type SessionKind string
+
+const (
+	Work       SessionKind = "pomodoro"
+	ShortBreak SessionKind = "short_break"
+	LongBreak  SessionKind = "long_break"
+)
+
+func (k *SessionKind) String() string {
+	return string(*k)
+}
+
+// Set accepts a session kind or its short name: work, short or long.
+func (k *SessionKind) Set(value string) error {
+	switch value {
+	case "work", string(Work):
+		*k = Work
+	case "short", string(ShortBreak):
+		*k = ShortBreak
+	case "long", string(LongBreak):
+		*k = LongBreak
+	default:
+		return fmt.Errorf("unknown session kind %q, want work, short or long", value)
+	}
+	return nil
+}
+
+// TabOrder lists the kinds of session to show tabs for, in order. As a flag
+// it is a comma-separated list of kinds, e.g. work,short.
+type TabOrder []SessionKind
+
+func (o *TabOrder) String() string {
+	if o == nil {
+		return ""
+	}
+	kinds := make([]string, len(*o))
+	for i, kind := range *o {
+		kinds[i] = string(kind)
+	}
+	return strings.Join(kinds, ",")
+}
+
+func (o *TabOrder) Set(value string) error {
+	var order TabOrder
+	for _, name := range strings.Split(value, ",") {
+		var kind SessionKind
+		if err := kind.Set(strings.TrimSpace(name)); err != nil {
+			return err
+		}
+		if slices.Contains(order, kind) {
+			return fmt.Errorf("%s listed twice", kind)
+		}
+		order = append(order, kind)
+	}
+	if !slices.Contains(order, Work) {
+		return errors.New("the tabs must include work")
+	}
+	*o = order
+	return nil
+}
+
+// Once is a single session to run and quit after, given as a session kind
+// or as a duration for a work session of that length, e.g. 25m.
+type Once struct {
+	Kind     SessionKind
+	Duration time.Duration
+}
+
+func (o *Once) String() string {
+	if o.Duration > 0 {
+		return o.Duration.String()
+	}
+	return string(o.Kind)
+}
+
+func (o *Once) Set(value string) error {
+	if d, err := time.ParseDuration(value); err == nil {
+		if d <= 0 {
+			return fmt.Errorf("invalid duration %q, want more than zero", value)
+		}
+		*o = Once{Kind: Work, Duration: d}
+		return nil
+	}
+	o.Duration = 0
+	if err := o.Kind.Set(value); err != nil {
+		return fmt.Errorf("%w, or a duration such as 25m", err)
+	}
+	return nil
+}
+
+// SleepPolicy decides what happens to a running session when the system
+// was suspended in the middle of it.
+type SleepPolicy string
+
+const (
+	// SleepCredit counts the time asleep towards the session.
+	SleepCredit SleepPolicy = "credit"
+	// SleepPause pauses the session at the point the system went to sleep.
+	SleepPause SleepPolicy = "pause"
+)
+
+func (p *SleepPolicy) String() string {
+	return string(*p)
+}
+
+func (p *SleepPolicy) Set(value string) error {
+	switch SleepPolicy(value) {
+	case SleepCredit, SleepPause:
+		*p = SleepPolicy(value)
+		return nil
+	default:
+		return fmt.Errorf("unknown sleep policy %q, want %q or %q", value, SleepCredit, SleepPause)
+	}
+}
+
+// BarChar is a single-width glyph drawn in the progress bar. The zero value
+// keeps the bar's default glyph.
+type BarChar rune
+
+func (c *BarChar) String() string {
+	if *c == 0 {
+		return ""
+	}
+	return string(*c)
+}
+
+func (c *BarChar) Set(value string) error {
+	runes := []rune(value)
+	if len(runes) != 1 || lipgloss.Width(value) != 1 {
+		return fmt.Errorf("%q is not a single-width character", value)
+	}
+	*c = BarChar(runes[0])
+	return nil
+}
+
+// NotifyBackend names the method used to send desktop notifications.
+type NotifyBackend string
+
+const (
+	NotifyBeeep     NotifyBackend = "beeep"
+	NotifySend      NotifyBackend = "notify-send"
+	NotifyOsascript NotifyBackend = "osascript"
+	NotifyNone      NotifyBackend = "none"
+)
+
+func (b *NotifyBackend) String() string {
+	return string(*b)
+}
+
+func (b *NotifyBackend) Set(value string) error {
+	switch NotifyBackend(value) {
+	case NotifyBeeep, NotifySend, NotifyOsascript, NotifyNone:
+		*b = NotifyBackend(value)
+		return nil
+	default:
+		return fmt.Errorf("unknown notification backend %q, want one of %s, %s, %s or %s", value, NotifyBeeep, NotifySend, NotifyOsascript, NotifyNone)
+	}
+}
+
+// LogTarget names where finished sessions are logged.
+type LogTarget string
+
+const (
+	// LogFile appends sessions to the history log.
+	LogFile LogTarget = "file"
+	// LogSyslog sends them to syslog, or journald, instead.
+	LogSyslog LogTarget = "syslog"
+	// LogBoth does both.
+	LogBoth LogTarget = "both"
+)
+
+func (t *LogTarget) String() string {
+	return string(*t)
+}
+
+func (t *LogTarget) Set(value string) error {
+	switch LogTarget(value) {
+	case LogFile, LogSyslog, LogBoth:
+		*t = LogTarget(value)
+		return nil
+	default:
+		return fmt.Errorf("unknown log target %q, want %s, %s or %s", value, LogFile, LogSyslog, LogBoth)
+	}
+}
+
+// OtherTabAction decides what space does on a tab other than the one whose
+// session is running. A paused session resumes from any tab.
+type OtherTabAction string
+
+const (
+	// OtherTabRestart discards the other session and starts this tab,
+	// asking first if ConfirmOverwrite is set.
+	OtherTabRestart OtherTabAction = "restart"
+	// OtherTabSwitch switches the view to the other session's tab.
+	OtherTabSwitch OtherTabAction = "switch"
+	// OtherTabIgnore does nothing but point at the other session.
+	OtherTabIgnore OtherTabAction = "ignore"
+)
+
+func (a *OtherTabAction) String() string {
+	return string(*a)
+}
+
+func (a *OtherTabAction) Set(value string) error {
+	switch OtherTabAction(value) {
+	case OtherTabRestart, OtherTabSwitch, OtherTabIgnore:
+		*a = OtherTabAction(value)
+		return nil
+	case "resume":
+		return fmt.Errorf("%q is no longer an other-tab action, as a paused session resumes from any tab; use %s to jump to the session's tab", value, OtherTabSwitch)
+	default:
+		return fmt.Errorf("unknown other-tab action %q, want %s, %s or %s", value, OtherTabRestart, OtherTabSwitch, OtherTabIgnore)
+	}
+}
+
+// SkipConfirm decides which sessions s asks before skipping.
+type SkipConfirm string
+
+const (
+	SkipConfirmNone SkipConfirm = "none"
+	SkipConfirmWork SkipConfirm = "work"
+	SkipConfirmAll  SkipConfirm = "all"
+)
+
+func (c *SkipConfirm) String() string {
+	return string(*c)
+}
+
+func (c *SkipConfirm) Set(value string) error {
+	switch SkipConfirm(value) {
+	case SkipConfirmNone, SkipConfirmWork, SkipConfirmAll:
+		*c = SkipConfirm(value)
+		return nil
+	default:
+		return fmt.Errorf("unknown skip confirmation %q, want %s, %s or %s", value, SkipConfirmNone, SkipConfirmWork, SkipConfirmAll)
+	}
+}
+
+// Rounding decides how precisely the remaining time is shown.
+type Rounding string
+
+const (
+	RoundSeconds Rounding = "seconds"
+	RoundNearest Rounding = "nearest"
+	RoundDown    Rounding = "down"
+)
+
+func (r *Rounding) String() string {
+	return string(*r)
+}
+
+func (r *Rounding) Set(value string) error {
+	switch Rounding(value) {
+	case RoundSeconds, RoundNearest, RoundDown:
+		*r = Rounding(value)
+		return nil
+	default:
+		return fmt.Errorf("unknown rounding %q, want %s, %s or %s", value, RoundSeconds, RoundNearest, RoundDown)
+	}
+}
+
+// ClockEmphasis picks which of the remaining and elapsed time of a session
+// is shown next to the bar, and which below it in small.
+type ClockEmphasis string
+
+const (
+	// ClockRemaining shows only the remaining time.
+	ClockRemaining ClockEmphasis = "remaining"
+	// ClockBoth shows the remaining time with the elapsed time below it.
+	ClockBoth ClockEmphasis = "both"
+	// ClockElapsed shows the elapsed time with the remaining time below it.
+	ClockElapsed ClockEmphasis = "elapsed"
+)
+
+func (c *ClockEmphasis) String() string {
+	return string(*c)
+}
+
+func (c *ClockEmphasis) Set(value string) error {
+	switch ClockEmphasis(value) {
+	case ClockRemaining, ClockBoth, ClockElapsed:
+		*c = ClockEmphasis(value)
+		return nil
+	default:
+		return fmt.Errorf("unknown clock %q, want %s, %s or %s", value, ClockRemaining, ClockBoth, ClockElapsed)
+	}
+}
+
+// BorderStyle picks the lines the tabs and the window are drawn with.
+type BorderStyle string
+
+const (
+	BorderRounded BorderStyle = "rounded"
+	BorderNormal  BorderStyle = "normal"
+	BorderThick   BorderStyle = "thick"
+	BorderDouble  BorderStyle = "double"
+	BorderNone    BorderStyle = "none"
+)
+
+func (b *BorderStyle) String() string {
+	return string(*b)
+}
+
+func (b *BorderStyle) Set(value string) error {
+	switch BorderStyle(value) {
+	case BorderRounded, BorderNormal, BorderThick, BorderDouble, BorderNone:
+		*b = BorderStyle(value)
+		return nil
+	default:
+		return fmt.Errorf("unknown border style %q, want %s, %s, %s, %s or %s", value, BorderRounded, BorderNormal, BorderThick, BorderDouble, BorderNone)
+	}
+}
+
+// Hour is an hour of the day, from 0 to 23.
+type Hour int
+
+func (h *Hour) String() string {
+	return strconv.Itoa(int(*h))
+}
+
+func (h *Hour) Set(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 || n > 23 {
+		return fmt.Errorf("invalid hour %q, want 0 to 23", value)
+	}
+	*h = Hour(n)
+	return nil
+}
+
+// QuietHours is a daily window of local time, given as start-end in 24-hour
+// time, e.g. 22:00-08:00. A window whose end comes before its start runs
+// past midnight. The zero value is no window.
+type QuietHours struct {
+	// Start and End are offsets from midnight; On is set once the window
+	// is.
+	Start, End time.Duration
+	On         bool
+}
+
+func (q *QuietHours) String() string {
+	if q == nil || !q.On {
+		return ""
+	}
+	clock := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return clock(q.Start) + "-" + clock(q.End)
+}
+
+func (q *QuietHours) Set(value string) error {
+	from, to, ok := strings.Cut(value, "-")
+	if !ok {
+		return fmt.Errorf("invalid quiet hours %q, want start-end, e.g. 22:00-08:00", value)
+	}
+	start, err := time.Parse("15:04", strings.TrimSpace(from))
+	if err != nil {
+		return fmt.Errorf("invalid quiet hours start %q, want e.g. 22:00", from)
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(to))
+	if err != nil {
+		return fmt.Errorf("invalid quiet hours end %q, want e.g. 08:00", to)
+	}
+	midnight := time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)
+	*q = QuietHours{Start: start.Sub(midnight), End: end.Sub(midnight), On: true}
+	return nil
+}
+
+// contains reports whether t falls in the window.
+func (q QuietHours) contains(t time.Time) bool {
+	if !q.On || q.Start == q.End {
+		return false
+	}
+	t = t.Local()
+	now := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if q.Start < q.End {
+		return now >= q.Start && now < q.End
+	}
+	return now >= q.Start || now < q.End
+}
+
+// TodoAction is what a completed pomodoro does to the todo.txt task it was
+// for.
+type TodoAction string
+
+const (
+	TodoNone     TodoAction = "none"
+	TodoCount    TodoAction = "count"
+	TodoComplete TodoAction = "done"
+)
+
+func (a *TodoAction) String() string {
+	return string(*a)
+}
+
+func (a *TodoAction) Set(value string) error {
+	switch TodoAction(value) {
+	case TodoNone, TodoCount, TodoComplete:
+		*a = TodoAction(value)
+		return nil
+	default:
+		return fmt.Errorf("unknown todo action %q, want %s, %s or %s", value, TodoNone, TodoCount, TodoComplete)
+	}
+}
+
+// HookEvent names a moment in a session that a hook command can run on.
+type HookEvent string
+
+const (
+	// EventSessionStart and EventSessionEnd are a work session starting and
+	// completing.
+	EventSessionStart HookEvent = "session_start"
+	EventSessionEnd   HookEvent = "session_end"
+	// EventBreakStart and EventBreakEnd are a break starting and completing.
+	EventBreakStart HookEvent = "break_start"
+	EventBreakEnd   HookEvent = "break_end"
+	// EventPause and EventResume are any session pausing and resuming.
+	EventPause  HookEvent = "pause"
+	EventResume HookEvent = "resume"
+)
+
+// Hooks maps events to the shell command run on each. As a flag it is set
+// once per event, as event=command.
+type Hooks map[HookEvent]string
+
+func (h *Hooks) String() string {
+	if h == nil {
+		return ""
+	}
+	var pairs []string
+	for event, command := range *h {
+		pairs = append(pairs, string(event)+"="+command)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (h *Hooks) Set(value string) error {
+	event, command, ok := strings.Cut(value, "=")
+	if !ok || command == "" {
+		return fmt.Errorf("invalid hook %q, want event=command", value)
+	}
+	switch HookEvent(event) {
+	case EventSessionStart, EventSessionEnd, EventBreakStart, EventBreakEnd, EventPause, EventResume:
+	default:
+		return fmt.Errorf("unknown hook event %q, want %s, %s, %s, %s, %s or %s", event, EventSessionStart, EventSessionEnd, EventBreakStart, EventBreakEnd, EventPause, EventResume)
+	}
+	if *h == nil {
+		*h = Hooks{}
+	}
+	(*h)[HookEvent(event)] = command
+	return nil
+}
+
+// Activities are suggestions for what to do on a break. As a flag it is set
+// once per activity.
+type Activities []string
+
+func (a *Activities) String() string {
+	if a == nil {
+		return ""
+	}
+	return strings.Join(*a, ",")
+}
+
+func (a *Activities) Set(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return errors.New("empty break activity")
+	}
+	*a = append(*a, value)
+	return nil
+}
+
+// Session defines one timer tab. The cycle runs the first Work session and
+// alternates it with the first ShortBreak and LongBreak sessions; any extra
+// sessions can still be started from their own tab.
+type Session struct {
+	Name     string
+	Kind     SessionKind
+	Duration time.Duration
+}
+
+// Config holds the user settings the initial model is built from.
+type Config struct {
+	// Sessions lists the timer tabs in display order.
+	Sessions []Session
+	// Start runs a single session of this kind on launch and quits once it
+	// completes. Empty starts nothing.
+	Start SessionKind
+	// AutoStart starts the first pomodoro on launch and carries on from
+	// there, unlike Start.
+	AutoStart bool
+	// Once runs a single session like Start, optionally of its own length,
+	// and prints how it went on quitting.
+	Once Once
+	// LongBreakInterval is the number of pomodoros between long breaks.
+	LongBreakInterval int
+	// RatioBreaks derives the break durations from the work duration: the
+	// short break lasts work/ShortBreakRatio and the long break
+	// work/LongBreakRatio.
+	RatioBreaks     bool
+	ShortBreakRatio float64
+	LongBreakRatio  float64
+	// FreeForm starts whichever tab is active on space instead of the next
+	// session in the pomodoro cycle.
+	FreeForm bool
+	// VerboseLog adds every pause and resume of a session to its history
+	// record.
+	VerboseLog bool
+	// LogTo is where sessions are logged. Without the history file the
+	// totals, goals and calendar have nothing to count.
+	LogTo LogTarget
+	// Bell rings the terminal bell alongside the desktop notification.
+	Bell bool
+	// MultiTimer gives every tab its own timer that can run alongside the
+	// others, instead of a single session at a time.
+	MultiTimer bool
+	// ConfirmOverwrite asks before starting a tab throws away the session
+	// running on another one.
+	ConfirmOverwrite bool
+	// OtherTab is what space does on a tab other than the running or
+	// paused session's.
+	OtherTab OtherTabAction
+	// ConfirmSkip is which sessions s asks about before skipping them.
+	ConfirmSkip SkipConfirm
+	// ConfirmQuit asks before q or esc quits while a pomodoro is running.
+	ConfirmQuit bool
+	// Strict keeps a pomodoro from being paused, reset or skipped once it
+	// has started. Breaks are left alone.
+	Strict bool
+	// LockTabs keeps the running session's tab in view until it is reset or
+	// completes.
+	LockTabs bool
+	// IdleQuit quits the app once it has been idle, with no key presses, for
+	// this long. Zero disables it.
+	IdleQuit time.Duration
+	// MaxSession stops a session that has run this long, whatever its
+	// duration, as a guard against a mistyped one. Zero disables it.
+	MaxSession time.Duration
+	// ShowPercent writes the percentage done next to the progress bar.
+	ShowPercent bool
+	// WatchProcess pauses a running pomodoro while no process of this name
+	// is running, and resumes it once one is.
+	WatchProcess string
+	// SnoozeFor is how long z puts the completion notification off for,
+	// up to MaxSnoozes times a session.
+	SnoozeFor  time.Duration
+	MaxSnoozes int
+	// MinBreak is the fraction of the break after a pomodoro that has to
+	// pass, from when the pomodoro completed, before the next can start.
+	// Zero disables it.
+	MinBreak float64
+	// Activities are suggested at random, one each time a break starts.
+	Activities Activities
+	// Clock is which of the remaining and elapsed time is shown large.
+	Clock ClockEmphasis
+	// AutoAdvance starts the next session of the cycle as soon as one
+	// completes, a break after a pomodoro and a pomodoro after a break.
+	AutoAdvance bool
+	// DoneHold is how long a completed session shows its full bar and
+	// "Done!" before it is logged and the timer reset.
+	DoneHold time.Duration
+	// WorkIcon and BreakIcon override the notification icon for completed
+	// work and break sessions.
+	WorkIcon  string
+	BreakIcon string
+	// ScorePomodoroWeight and ScoreMinuteWeight weigh today's completed
+	// pomodoros and minutes of focus in the focus score.
+	ScorePomodoroWeight float64
+	ScoreMinuteWeight   float64
+	// SleepPolicy handles a session the system was suspended during.
+	SleepPolicy SleepPolicy
+	// BarFull and BarEmpty replace the glyphs of the filled and empty parts
+	// of the progress bar.
+	BarFull  BarChar
+	BarEmpty BarChar
+	// PreBreakLead sends a heads-up notification this long before a
+	// pomodoro ends. Zero disables it.
+	PreBreakLead time.Duration
+	// Notify picks the desktop notification backend.
+	Notify NotifyBackend
+	// SetTitle shows the remaining time of the current session in the
+	// terminal title.
+	SetTitle bool
+	// DailySummary sends a notification with the day's totals when the
+	// day rolls over, at midnight or the -day-start hour.
+	DailySummary bool
+	// FineCountdown ticks in tenths of a second during the last ten seconds
+	// of a session.
+	FineCountdown bool
+	// Profile tags every logged session, so focus time can be compared
+	// across contexts such as coding and writing.
+	Profile string
+	// ProfileStats limits the stats to sessions of Profile instead of
+	// combining all of them.
+	ProfileStats bool
+	// BigClock shows the remaining time in large block digits, readable
+	// from across the room.
+	BigClock bool
+	// Transcript runs inline instead of drawing the UI and prints a
+	// readable line for every change of the timers.
+	Transcript bool
+	// ShowLifetime shows the number of pomodoros ever completed.
+	ShowLifetime bool
+	// CleanOnly counts a pomodoro towards today's totals and the focus score
+	// only if it was paused for no longer than MaxPause in all. Others are
+	// still logged, flagged as not clean.
+	CleanOnly bool
+	MaxPause  time.Duration
+	// Goal is the number of pomodoros to aim for each day. Zero sets no
+	// goal.
+	Goal int
+	// GoalStop stops AutoAdvance once today's goal is met.
+	GoalStop bool
+	// Repaint redraws the UI at this interval, keeping it fresh while no
+	// session ticks. Zero disables it.
+	Repaint time.Duration
+	// Rounding shows the remaining time in whole minutes, rounded to the
+	// nearest or down, until the last minute, which always shows seconds.
+	Rounding Rounding
+	// AskReason asks why a running session is being reset and logs the
+	// answer with the abandoned session.
+	AskReason bool
+	// Tones beep a rising tone when a session starts and a falling one when
+	// it completes, or a plain bell where the speaker cannot be driven.
+	Tones bool
+	// Bare renders only the progress bar and remaining time, for embedding
+	// in other layouts.
+	Bare bool
+	// Cooldown keeps a new session from starting this long after one
+	// completes. Zero disables it.
+	Cooldown time.Duration
+	// StartDelay counts down this long after space before a pomodoro
+	// starts, to get ready for it. Zero disables it.
+	StartDelay time.Duration
+	// FocusWork opens the app on the work tab. It takes precedence over any
+	// other source of the initial tab, such as restored state, but not over
+	// Start, which opens on the session it starts.
+	FocusWork bool
+	// Width lays the app out to this many columns whatever the size of the
+	// terminal, clipping anything wider. Zero fits the layout to the tabs.
+	Width int
+	// Plain renders the timer as a line of text without box drawing, for
+	// screen readers.
+	Plain bool
+	// PauseOnBlur pauses the running session while the terminal is out of
+	// focus and resumes it when focus returns.
+	PauseOnBlur bool
+	// Hooks are shell commands run when a session starts, completes, pauses
+	// or resumes.
+	Hooks Hooks
+	// Vertical draws the progress bar as a column that fills upward.
+	Vertical bool
+	// Border is the style of the lines around the tabs and the window.
+	Border BorderStyle
+	// Theme names the colors of the UI, one of themes.
+	Theme string
+	// KeysFooter shows a line of the most common keys under the window.
+	KeysFooter bool
+	// AskNote asks for a note on each completed pomodoro and logs it with
+	// the session.
+	AskNote bool
+	// LongBreakETA shows the time left until the next long break, counting
+	// the rest of the current session and the sessions before the break.
+	LongBreakETA bool
+	// DoneTitle and DoneMessage replace the title and body of the
+	// notification of a completed session. They are text/template
+	// templates over completionData.
+	DoneTitle   string
+	DoneMessage string
+	// QuietHours keeps notifications, tones and the bell quiet in this
+	// window of the day; completions still show in the app.
+	QuietHours QuietHours
+	// TodoFile is a todo.txt file to pick the task of a session from.
+	TodoFile string
+	// TodoAction is what a completed pomodoro does to its task: nothing,
+	// count it in a pomo: tag, or mark the task done.
+	TodoAction TodoAction
+	// Events runs the daemon with a stream of JSON events on stdout, one
+	// per change of the timers; EventTicks adds one every tick.
+	Events     bool
+	EventTicks bool
+	// TabOrder, if set, picks the Sessions shown and their order; the
+	// others are left out of the tabs and the cycle.
+	TabOrder TabOrder
+}
+
+// ApplyOnce turns Once into the Start session it runs, at its duration.
+func (c *Config) ApplyOnce() {
+	if c.Once.Kind == "" {
+		return
+	}
+	c.Start = c.Once.Kind
+	if c.Once.Duration == 0 {
+		return
+	}
+	c.setDuration(c.Once.Kind, c.Once.Duration)
+}
+
+// duration returns the duration of the first session of kind, or 0 if
+// there is none.
+func (c *Config) duration(kind SessionKind) time.Duration {
+	for _, session := range c.Sessions {
+		if session.Kind == kind {
+			return session.Duration
+		}
+	}
+	return 0
+}
+
+// setDuration sets the duration of the sessions of kind to d.
+func (c *Config) setDuration(kind SessionKind, d time.Duration) {
+	for i := range c.Sessions {
+		if c.Sessions[i].Kind == kind {
+			c.Sessions[i].Duration = d
+		}
+	}
+}
+
+// ApplyTabs keeps the Sessions of the kinds in TabOrder, in its order.
+func (c *Config) ApplyTabs() {
+	if len(c.TabOrder) == 0 {
+		return
+	}
+	var sessions []Session
+	for _, kind := range c.TabOrder {
+		for _, session := range c.Sessions {
+			if session.Kind == kind {
+				sessions = append(sessions, session)
+			}
+		}
+	}
+	c.Sessions = sessions
+}
+
+func defaultConfig() Config {
+	return Config{
+		Sessions: []Session{
+			{Name: "Pomodoro", Kind: Work, Duration: 25 * time.Minute},
+			{Name: "Short break", Kind: ShortBreak, Duration: 5 * time.Minute},
+			{Name: "Long break", Kind: LongBreak, Duration: 15 * time.Minute},
+		},
+		LongBreakInterval:   4,
+		ShortBreakRatio:     5,
+		LongBreakRatio:      3,
+		FreeForm:            false,
+		ConfirmOverwrite:    true,
+		ConfirmQuit:         true,
+		OtherTab:            OtherTabRestart,
+		ConfirmSkip:         SkipConfirmWork,
+		ScorePomodoroWeight: 10,
+		ScoreMinuteWeight:   1,
+		SleepPolicy:         SleepCredit,
+		Notify:              NotifyBeeep,
+		Repaint:             10 * time.Second,
+		Rounding:            RoundSeconds,
+		SetTitle:            true,
+		Border:              BorderRounded,
+		Theme:               defaultTheme,
+		TodoAction:          TodoNone,
+		MaxSession:          4 * time.Hour,
+		LogTo:               LogFile,
+		SnoozeFor:           5 * time.Minute,
+		MaxSnoozes:          3,
+		Clock:               ClockRemaining,
+		DoneHold:            time.Second,
+		AutoAdvance:         true,
+		KeysFooter:          true,
+	}
+}
@@ -0,0 +1,237 @@
+package pomodoro
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// configKinds are the keys of the config file that set the duration of each
+// kind of session.
+var configKinds = map[string]SessionKind{
+	"pomodoro":    Work,
+	"short_break": ShortBreak,
+	"long_break":  LongBreak,
+}
+
+func configPath() (string, error) {
+	dir, err := configHome()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "pomodoro", "config.toml"), nil
+}
+
+// LoadConfig returns defaultConfig with the session durations set in the
+// config file, e.g. pomodoro = "25m", along with strict, confirm_quit and
+// theme. A POMODORO_ environment variable named after a key, such as
+// POMODORO_SHORT_BREAK=10m, overrides the file. A missing file leaves the
+// defaults. The returned Config is always usable: a key that cannot be read
+// keeps its default and is reported in the error instead.
+func LoadConfig() (Config, error) {
+	cfg := defaultConfig()
+	errs := readConfigFile(&cfg)
+	for _, key := range configKeys {
+		name := "POMODORO_" + strings.ToUpper(key)
+		if value := os.Getenv(name); value != "" {
+			if err := cfg.set(key, value); err != nil {
+				errs = append(errs, fmt.Errorf("$%s: %w", name, err))
+			}
+		}
+	}
+	return cfg, errors.Join(errs...)
+}
+
+// configKeys are the keys of the config file, which can also be set from
+// the environment.
+var configKeys = []string{"pomodoro", "short_break", "long_break", "strict", "confirm_quit", "theme"}
+
+// keyFlags are the command-line flags of the config keys whose flag is
+// named differently.
+var keyFlags = map[string]string{
+	"short_break":  "short",
+	"long_break":   "long",
+	"confirm_quit": "confirm-quit",
+}
+
+// reloadMsg carries the config read again by ReloadConfig, or the error
+// that keeps it from being applied.
+type reloadMsg struct {
+	Config Config
+	Err    error
+}
+
+// ReloadConfig reads the config file and the environment again, as
+// LoadConfig does, with the flags set on fs still overriding them. The
+// returned message applies the durations, strict, confirm_quit and theme to
+// a running Model, or warns and keeps the old ones if any key cannot be read.
+func ReloadConfig(fs *flag.FlagSet) tea.Msg {
+	cfg, err := LoadConfig()
+	if _, ok := themes[cfg.Theme]; !ok {
+		err = errors.Join(err, fmt.Errorf("unknown theme %q", cfg.Theme))
+	}
+	fs.Visit(func(f *flag.Flag) {
+		for _, key := range configKeys {
+			name, ok := keyFlags[key]
+			if !ok {
+				name = key
+			}
+			if f.Name == name {
+				cfg.set(key, f.Value.String())
+			}
+		}
+	})
+	return reloadMsg{Config: cfg, Err: err}
+}
+
+// reload applies the durations, strict, confirm_quit and theme of cfg. A
+// session that is on keeps its length; the new durations take effect from
+// the next one.
+func (m *Model) reload(cfg Config) {
+	lengths := make([]time.Duration, len(m.Tabs))
+	for i := range m.Tabs {
+		lengths[i] = m.getDurationByIndex(i)
+		m.Tabs[i].Duration = cfg.duration(m.Tabs[i].Kind)
+	}
+	m.scaleBreaks()
+	for i := range m.Tabs {
+		if m.Timers[i].Status != Idle {
+			m.Timers[i].Extra = lengths[i] - m.Tabs[i].Duration
+		}
+	}
+	m.Strict, m.ConfirmQuit = cfg.Strict, cfg.ConfirmQuit
+
+	SetTheme(cfg.Theme)
+	for i := range m.ProgressBars {
+		progress.WithGradient(theme.Highlight, theme.HighlightEnd)(&m.ProgressBars[i])
+	}
+	progress.WithSolidFill(theme.Special.Dark)(&m.GoalBar)
+}
+
+// readConfigFile sets cfg from the config file and returns the errors of the
+// lines it could not read.
+func readConfigFile(cfg *Config) []error {
+	path, err := configPath()
+	if err != nil {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return []error{err}
+	}
+	defer f.Close()
+
+	var errs []error
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		key, value, err := parseConfigLine(scanner.Text())
+		if err == nil && key != "" {
+			err = cfg.set(key, value)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s:%d: %w", path, line, err))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// set sets the config key to value, leaving c as it was if value cannot be
+// read.
+func (c *Config) set(key, value string) error {
+	if key == "theme" {
+		c.Theme = value
+		return nil
+	}
+	if field, ok := map[string]*bool{"strict": &c.Strict, "confirm_quit": &c.ConfirmQuit}[key]; ok {
+		on, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q, want true or false", key, value)
+		}
+		*field = on
+		return nil
+	}
+	kind, ok := configKinds[key]
+	if !ok {
+		return fmt.Errorf("unknown key %q", key)
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return fmt.Errorf("invalid %s %q, want a duration like \"25m\"", key, value)
+	}
+	c.setDuration(kind, d)
+	return nil
+}
+
+// parseConfigLine splits a line of the config file into its key and value.
+// Only the part of TOML the file needs is understood: key = "value" pairs,
+// comments and blank lines, which return an empty key.
+func parseConfigLine(line string) (key, value string, err error) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", "", nil
+	}
+
+	key, value, ok := strings.Cut(line, "=")
+	if !ok {
+		return "", "", fmt.Errorf("want key = \"value\", got %q", line)
+	}
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+	if strings.HasPrefix(value, `"`) {
+		end := strings.Index(value[1:], `"`)
+		if end < 0 {
+			return "", "", fmt.Errorf("unterminated string for %s", key)
+		}
+		rest := strings.TrimSpace(value[end+2:])
+		if rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", "", fmt.Errorf("unexpected %q after the value of %s", rest, key)
+		}
+		value, err = strconv.Unquote(value[:end+2])
+		if err != nil {
+			return "", "", fmt.Errorf("invalid string for %s: %w", key, err)
+		}
+		return key, value, nil
+	}
+	value, _, _ = strings.Cut(value, "#")
+	return key, strings.TrimSpace(value), nil
+}
+
+// DurationFlags defines -pomodoro, -short and -long on fs, defaulting to the
+// durations in c. The function it returns sets the durations of c from them
+// once fs is parsed; a zero duration turns its session off.
+func (c *Config) DurationFlags(fs *flag.FlagSet) func() error {
+	durations := []struct {
+		name string
+		kind SessionKind
+		d    *time.Duration
+	}{
+		{"pomodoro", Work, fs.Duration("pomodoro", c.duration(Work), "length of a pomodoro, e.g. 50m; overrides the config file")},
+		{"short", ShortBreak, fs.Duration("short", c.duration(ShortBreak), "length of a short break, e.g. 10m; overrides the config file")},
+		{"long", LongBreak, fs.Duration("long", c.duration(LongBreak), "length of a long break, e.g. 20m; overrides the config file")},
+	}
+	return func() error {
+		for _, duration := range durations {
+			if *duration.d < 0 {
+				return fmt.Errorf("invalid -%s %s, want a duration like 25m, or 0 to turn the session off", duration.name, *duration.d)
+			}
+			c.setDuration(duration.kind, *duration.d)
+		}
+		return nil
+	}
+}
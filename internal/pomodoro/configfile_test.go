@@ -0,0 +1,177 @@
+package pomodoro
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfig writes the config file the isolated test reads.
+func writeConfig(t *testing.T, content string) {
+	t.Helper()
+	path, err := configPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadConfigZeroTurnsSessionOff(t *testing.T) {
+	isolate(t)
+	writeConfig(t, "short_break = \"0s\"\nlong_break = \"-5m\"\n")
+
+	cfg, err := LoadConfig()
+	if err == nil {
+		t.Error("a negative long_break was not reported")
+	}
+	if d := cfg.duration(ShortBreak); d != 0 {
+		t.Errorf("short break = %s, want 0s", d)
+	}
+	if d := cfg.duration(LongBreak); d != 15*time.Minute {
+		t.Errorf("long break = %s, want the default 15m0s", d)
+	}
+
+	m := NewModel(cfg, nil)
+	if mode := m.indexOfKind(ShortBreak); !m.disabled(mode) {
+		t.Error("the short break is not turned off")
+	}
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want [3]time.Duration // pomodoro, short break, long break
+	}{
+		{"file", nil, nil, [3]time.Duration{50 * time.Minute, 10 * time.Minute, 15 * time.Minute}},
+		{
+			"file and env",
+			map[string]string{"POMODORO_POMODORO": "40m", "POMODORO_LONG_BREAK": "30m"},
+			nil,
+			[3]time.Duration{40 * time.Minute, 10 * time.Minute, 30 * time.Minute},
+		},
+		{
+			"file, env and flag",
+			map[string]string{"POMODORO_POMODORO": "40m", "POMODORO_LONG_BREAK": "30m"},
+			[]string{"-pomodoro", "45m", "-short", "7m"},
+			[3]time.Duration{45 * time.Minute, 7 * time.Minute, 30 * time.Minute},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolate(t)
+			writeConfig(t, "pomodoro = \"50m\"\nshort_break = \"10m\"\n")
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			cfg, err := LoadConfig()
+			if err != nil {
+				t.Fatal(err)
+			}
+			fs := flag.NewFlagSet("pomodoro", flag.ContinueOnError)
+			setDurations := cfg.DurationFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if err := setDurations(); err != nil {
+				t.Fatal(err)
+			}
+
+			for i, kind := range []SessionKind{Work, ShortBreak, LongBreak} {
+				if got := cfg.duration(kind); got != tt.want[i] {
+					t.Errorf("%s = %s, want %s", kind, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestConfigEnvError(t *testing.T) {
+	isolate(t)
+	t.Setenv("POMODORO_STRICT", "maybe")
+
+	cfg, err := LoadConfig()
+	if err == nil || !strings.Contains(err.Error(), "$POMODORO_STRICT") {
+		t.Errorf("error = %v, want one naming $POMODORO_STRICT", err)
+	}
+	if cfg.Strict != defaultConfig().Strict {
+		t.Error("an unreadable POMODORO_STRICT changed strict")
+	}
+}
+
+func TestDurationFlagZeroTurnsSessionOff(t *testing.T) {
+	tests := []struct {
+		args    []string
+		wantErr bool
+	}{
+		{[]string{"-short", "0"}, false},
+		{[]string{"-short", "-5m"}, true},
+	}
+	for _, tt := range tests {
+		cfg := defaultConfig()
+		fs := flag.NewFlagSet("pomodoro", flag.ContinueOnError)
+		setDurations := cfg.DurationFlags(fs)
+		if err := fs.Parse(tt.args); err != nil {
+			t.Fatal(err)
+		}
+		err := setDurations()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%v: error = %v, want one: %t", tt.args, err, tt.wantErr)
+		}
+		if !tt.wantErr && cfg.duration(ShortBreak) != 0 {
+			t.Errorf("%v: short break = %s, want 0s", tt.args, cfg.duration(ShortBreak))
+		}
+	}
+}
+
+func TestReloadConfig(t *testing.T) {
+	isolate(t)
+	m := NewModel(defaultConfig(), nil)
+	work, short := m.workIndex(), m.indexOfKind(ShortBreak)
+	m.startTimer(work)
+
+	writeConfig(t, "pomodoro = \"50m\"\nshort_break = \"10m\"\nstrict = true\n")
+	cfg := defaultConfig()
+	fs := flag.NewFlagSet("pomodoro", flag.ContinueOnError)
+	cfg.DurationFlags(fs)
+	if err := fs.Parse([]string{"-short", "7m"}); err != nil {
+		t.Fatal(err)
+	}
+	m, _ = send(m, ReloadConfig(fs))
+	if m.Toast != "Config reloaded" {
+		t.Errorf("Toast = %q, want Config reloaded", m.Toast)
+	}
+	if d := m.getDurationByIndex(work); d != 25*time.Minute {
+		t.Errorf("the running pomodoro is %s long, want it to keep its 25m0s", d)
+	}
+	if d := m.getDurationByIndex(short); d != 7*time.Minute {
+		t.Errorf("short break = %s, want the flag's 7m0s over the file", d)
+	}
+	if !m.Strict {
+		t.Error("strict was not reloaded")
+	}
+	m.resetTimer(work)
+	if d := m.getDurationByIndex(work); d != 50*time.Minute {
+		t.Errorf("the next pomodoro is %s long, want the file's 50m0s", d)
+	}
+
+	// An unreadable value keeps the settings as they were.
+	writeConfig(t, "pomodoro = \"soon\"\n")
+	m, _ = send(m, ReloadConfig(fs))
+	if !strings.HasPrefix(m.Toast, "Config not reloaded") {
+		t.Errorf("Toast = %q, want a warning", m.Toast)
+	}
+	if d := m.getDurationByIndex(work); d != 50*time.Minute || !m.Strict {
+		t.Errorf("pomodoro = %s and strict = %t after a bad reload, want 50m0s and true", d, m.Strict)
+	}
+}
@@ -0,0 +1,137 @@
+package pomodoro
+
+import (
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keyMsg builds the key press the TUI would deliver for key.
+func keyMsg(key string) tea.KeyMsg {
+	if key == " " {
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(key)}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+// RunDaemon drives the model's Update loop without a terminal UI, so the
+// timer and notifications behave exactly as in the TUI. The first session
+// starts right away; daemonSignals map signals to key presses for control
+// and SIGINT or SIGTERM stop the daemon. With Events, every change of the
+// timers is written to stdout as a streamEvent.
+func RunDaemon(m Model) error {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	for sig := range daemonSignals {
+		signal.Notify(sigs, sig)
+	}
+	defer signal.Stop(sigs)
+
+	msgs := make(chan tea.Msg)
+	run := func(cmd tea.Cmd) {
+		if cmd != nil {
+			go func() { msgs <- cmd() }()
+		}
+	}
+
+	// There is no terminal to print to, whose title could show the session
+	// or to type a note or the reason for a reset in.
+	m.SetTitle = false
+	m.Transcript = false
+	m.AskNote = false
+	m.AskReason = false
+	// Nobody could answer an offer to restore the history log's backup.
+	m.Confirm = nil
+
+	current := m
+	update := func(msg tea.Msg) {
+		prev := current
+		prev.Timers = append([]timer(nil), current.Timers...)
+		next, cmd := current.Update(msg)
+		current = next.(Model)
+		if current.Events {
+			writeEvents(os.Stdout, current.events(prev, msg))
+		}
+		run(cmd)
+	}
+	run(current.Init())
+	update(keyMsg(" "))
+
+	for {
+		var msg tea.Msg
+		select {
+		case sig := <-sigs:
+			key, ok := daemonSignals[sig]
+			if !ok {
+				return nil
+			}
+			msg = keyMsg(key)
+		case msg = <-msgs:
+		}
+
+		switch msg := msg.(type) {
+		case nil:
+			continue
+		case tea.QuitMsg:
+			return nil
+		case tea.BatchMsg:
+			for _, cmd := range msg {
+				run(cmd)
+			}
+			continue
+		}
+		if cmds, ok := sequenceCmds(msg); ok {
+			go func() {
+				for _, cmd := range cmds {
+					runInOrder(cmd, msgs)
+				}
+			}()
+			continue
+		}
+
+		update(msg)
+	}
+}
+
+// sequenceCmds returns the commands of the msg tea.Sequence returns, whose
+// type bubbletea keeps to itself: a slice of commands that is not a
+// tea.BatchMsg.
+func sequenceCmds(msg tea.Msg) ([]tea.Cmd, bool) {
+	v := reflect.ValueOf(msg)
+	if v.Kind() != reflect.Slice || v.Type().Elem() != reflect.TypeOf(tea.Cmd(nil)) {
+		return nil, false
+	}
+	return v.Convert(reflect.TypeOf([]tea.Cmd(nil))).Interface().([]tea.Cmd), true
+}
+
+// runInOrder runs cmd and sends its msg on to msgs before returning, as
+// bubbletea does for each command of a sequence. A batch the command returns
+// is run in full first.
+func runInOrder(cmd tea.Cmd, msgs chan<- tea.Msg) {
+	if cmd == nil {
+		return
+	}
+	msg := cmd()
+	batch, ok := msg.(tea.BatchMsg)
+	if !ok {
+		msgs <- msg
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, cmd := range batch {
+		if cmd == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(cmd tea.Cmd) {
+			defer wg.Done()
+			msgs <- cmd()
+		}(cmd)
+	}
+	wg.Wait()
+}
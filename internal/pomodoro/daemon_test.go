@@ -0,0 +1,30 @@
+package pomodoro
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRunDaemonQuitsAfterOneShot(t *testing.T) {
+	isolate(t)
+	cfg := defaultConfig()
+	cfg.Sessions[0].Duration = time.Second
+	cfg.Start = Work
+	cfg.DoneHold = 0
+	notifier := &fakeNotifier{}
+	m := NewModel(cfg, notifier)
+
+	done := make(chan error, 1)
+	go func() { done <- RunDaemon(m) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("RunDaemon did not return after the session completed")
+	}
+	if notifier.count() != 1 {
+		t.Errorf("got %d completion alerts, want 1", notifier.count())
+	}
+}
@@ -0,0 +1,39 @@
+package pomodoro
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+func distractionsPath() (string, error) {
+	dir, err := dataHome()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "pomodoro", "distractions.txt"), nil
+}
+
+// logDistraction appends note to the distraction list, stamped with when it
+// was jotted down and the session it interrupted, e.g.
+// "2024-03-01 09:41 [Pomodoro] call the bank".
+func logDistraction(at time.Time, session, note string) error {
+	path, err := distractionsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "%s [%s] %s\n", at.Local().Format("2006-01-02 15:04"), session, note)
+	return err
+}
@@ -0,0 +1,84 @@
+package pomodoro
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// eventsVersion is the current format of the -events stream. Fields may be
+// added to it, but are not renamed or removed without bumping it.
+const eventsVersion = 1
+
+// streamEvent is one line of the -events stream, e.g.
+//
+//	{"version":1,"event":"start","at":"2024-03-01T09:00:00+01:00","name":"Pomodoro","type":"pomodoro","duration_seconds":1500,"remaining_seconds":1500}
+type streamEvent struct {
+	Version int `json:"version"`
+	// Event is start, pause, resume, restart, reset, complete or, with
+	// -event-ticks, tick.
+	Event            string      `json:"event"`
+	At               time.Time   `json:"at"`
+	Name             string      `json:"name"`
+	Type             SessionKind `json:"type"`
+	DurationSeconds  int64       `json:"duration_seconds"`
+	RemainingSeconds int64       `json:"remaining_seconds"`
+}
+
+// streamEvents are the -events names of each timerChange.
+var streamEvents = map[timerChange]string{
+	timerStarted:   "start",
+	timerPaused:    "pause",
+	timerResumed:   "resume",
+	timerRestarted: "restart",
+	timerReset:     "reset",
+	timerCompleted: "complete",
+}
+
+// events returns the stream events for how the timers changed from prev to
+// m in response to msg, in tab order.
+func (m Model) events(prev Model, msg tea.Msg) []streamEvent {
+	at := time.Now().Truncate(time.Second)
+	event := func(name string, mode int, from Model) streamEvent {
+		_, remaining := from.tabProgress(mode)
+		return streamEvent{
+			Version:          eventsVersion,
+			Event:            name,
+			At:               at,
+			Name:             m.Tabs[mode].Name,
+			Type:             m.Tabs[mode].Kind,
+			DurationSeconds:  int64(m.getDurationByIndex(mode).Seconds()),
+			RemainingSeconds: int64(remaining.Seconds()),
+		}
+	}
+
+	changes := m.changes(prev)
+	var events []streamEvent
+	for i := range m.Timers {
+		for _, change := range changes[i] {
+			// A reset timer has forgotten how far it got.
+			from := m
+			if change == timerReset || change == timerCompleted {
+				from = prev
+			}
+			events = append(events, event(streamEvents[change], i, from))
+		}
+	}
+	if tick, ok := msg.(tickMsg); ok && m.EventTicks && len(events) == 0 && m.Timers[tick.Mode].Status == Running && !m.Timers[tick.Mode].Done {
+		events = append(events, event("tick", tick.Mode, m))
+	}
+	return events
+}
+
+// writeEvents writes events to w one JSON object per line.
+func writeEvents(w io.Writer, events []streamEvent) error {
+	enc := json.NewEncoder(w)
+	for _, event := range events {
+		if err := enc.Encode(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
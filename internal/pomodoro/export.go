@@ -0,0 +1,162 @@
+package pomodoro
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Export writes the history log to a CSV file at outPath, one row per
+// session, or with weekly or monthly the totals of each week or month. The
+// totals count like the stats in the app, by ProfileStats and CleanOnly; the
+// sessions are all written, with their profile and flags as columns.
+func Export(cfg Config, outPath string, weekly, monthly bool) error {
+	path, err := historyPath()
+	if err != nil {
+		return err
+	}
+	if !weekly && !monthly {
+		return exportCSV(path, outPath)
+	}
+	return exportTotalsCSV(path, outPath, monthly, cfg.counted)
+}
+
+// exportCSV writes every record in the history log at historyPath to a CSV
+// file at outPath, one row per session under a header row. A missing or
+// empty log writes just the header.
+func exportCSV(historyPath, outPath string) error {
+	records, err := readHistoryFile(historyPath)
+	if err != nil {
+		return err
+	}
+	return writeCSVFile(outPath, func(w io.Writer) error {
+		return writeRecordsCSV(w, records)
+	})
+}
+
+// exportTotalsCSV writes the totals of the records in the history log at
+// historyPath that filter keeps to a CSV file at outPath, one row per week
+// or, with monthly, per month.
+func exportTotalsCSV(historyPath, outPath string, monthly bool, filter func([]sessionRecord) []sessionRecord) error {
+	records, err := readHistoryFile(historyPath)
+	if err != nil {
+		return err
+	}
+	return writeCSVFile(outPath, func(w io.Writer) error {
+		return writeTotalsCSV(w, filter(records), monthly)
+	})
+}
+
+// writeCSVFile creates the file at path and writes it out with write.
+func writeCSVFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeRecordsCSV writes records as CSV. The day in the date column is the
+// one the session counts to, by -day-start.
+func writeRecordsCSV(w io.Writer, records []sessionRecord) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"date", "type", "duration_minutes", "completed_at", "started_at", "name", "profile", "task", "abandoned", "skipped", "not_clean"})
+	for _, record := range records {
+		cw.Write([]string{
+			statsDay(record.CompletedAt).Format(goalDateFormat),
+			string(record.Type),
+			strconv.FormatFloat(float64(record.DurationSeconds)/60, 'f', -1, 64),
+			record.CompletedAt.Format(time.RFC3339),
+			record.StartedAt.Format(time.RFC3339),
+			record.Name,
+			record.Profile,
+			record.Task,
+			strconv.FormatBool(record.Abandoned),
+			strconv.FormatBool(record.Skipped),
+			strconv.FormatBool(record.NotClean),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// periodTotals adds up the sessions of a week or a month.
+type periodTotals struct {
+	Label                   string
+	Start                   time.Time
+	Pomodoros               int
+	Focus                   time.Duration
+	ShortBreaks, LongBreaks int
+	SkippedBreaks           int
+	Abandoned               int
+}
+
+// writeTotalsCSV writes the totals of records by week, from Monday, or by
+// month, oldest first. Weeks are labelled by ISO week, e.g. 2024-W09, and
+// months like 2024-03. Periods without sessions are left out.
+func writeTotalsCSV(w io.Writer, records []sessionRecord, monthly bool) error {
+	periods := make(map[time.Time]*periodTotals)
+	for _, record := range records {
+		t := statsDay(record.CompletedAt)
+		start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+		var label string
+		if monthly {
+			start = start.AddDate(0, 0, 1-start.Day())
+			label = start.Format("2006-01")
+		} else {
+			start = start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+			year, week := start.ISOWeek()
+			label = fmt.Sprintf("%d-W%02d", year, week)
+		}
+
+		period := periods[start]
+		if period == nil {
+			period = &periodTotals{Label: label, Start: start}
+			periods[start] = period
+		}
+		switch {
+		case record.skippedBreak():
+			period.SkippedBreaks++
+		case record.Abandoned:
+			period.Abandoned++
+		case record.Type == Work:
+			period.Pomodoros++
+			period.Focus += time.Duration(record.DurationSeconds) * time.Second
+		case record.Type == ShortBreak:
+			period.ShortBreaks++
+		case record.Type == LongBreak:
+			period.LongBreaks++
+		}
+	}
+
+	sorted := make([]*periodTotals, 0, len(periods))
+	for _, period := range periods {
+		sorted = append(sorted, period)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"period", "start", "pomodoros", "focus_minutes", "short_breaks", "long_breaks", "breaks_skipped", "abandoned"})
+	for _, period := range sorted {
+		cw.Write([]string{
+			period.Label,
+			period.Start.Format(goalDateFormat),
+			strconv.Itoa(period.Pomodoros),
+			strconv.FormatFloat(period.Focus.Minutes(), 'f', -1, 64),
+			strconv.Itoa(period.ShortBreaks),
+			strconv.Itoa(period.LongBreaks),
+			strconv.Itoa(period.SkippedBreaks),
+			strconv.Itoa(period.Abandoned),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,41 @@
+package pomodoro
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Terminals that support focus reporting send focusIn and focusOut once it
+// is turned on with EnableFocusReporting.
+const (
+	EnableFocusReporting  = "\x1b[?1004h"
+	DisableFocusReporting = "\x1b[?1004l"
+)
+
+// focusMsg and blurMsg report the terminal gaining and losing focus.
+type (
+	focusMsg struct{}
+	blurMsg  struct{}
+)
+
+// focusEvent translates the focus reports of the terminal into focusMsg and
+// blurMsg. This version of bubbletea does not parse them itself but passes
+// them on as unknown CSI sequences, which print as "?CSI[73]?" for the
+// final byte I of a focus in and "?CSI[79]?" for the O of a focus out.
+func focusEvent(msg tea.Msg) (tea.Msg, bool) {
+	s, ok := msg.(fmt.Stringer)
+	if !ok {
+		return nil, false
+	}
+	if _, isKey := msg.(tea.KeyMsg); isKey {
+		return nil, false
+	}
+	switch s.String() {
+	case fmt.Sprintf("?CSI%+v?", []byte("I")):
+		return focusMsg{}, true
+	case fmt.Sprintf("?CSI%+v?", []byte("O")):
+		return blurMsg{}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,125 @@
+package pomodoro
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// goalDateFormat keys the goals file by the day of statsDay.
+const goalDateFormat = "2006-01-02"
+
+// goalsVersion is the current format of the goals file. Version 1 was a
+// bare object of goals by day, without the version.
+const goalsVersion = 2
+
+type goalsFile struct {
+	Version int            `json:"version"`
+	Goals   map[string]int `json:"goals"`
+}
+
+func goalsPath() (string, error) {
+	dir, err := dataHome()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "pomodoro", "goals.json"), nil
+}
+
+// readGoals returns the daily goals set from inside the app, keyed by day. A
+// missing file has no goals.
+func readGoals() (map[string]int, error) {
+	path, err := goalsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return map[string]int{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file goalsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	if file.Version == 0 {
+		// A version 1 file holds the goals at the top level.
+		file.Goals = map[string]int{}
+		if err := json.Unmarshal(data, &file.Goals); err != nil {
+			return nil, err
+		}
+	}
+	if file.Goals == nil {
+		file.Goals = map[string]int{}
+	}
+	return file.Goals, nil
+}
+
+// dayGoal returns the goal set for the day of now, if there is one.
+func dayGoal(now time.Time) (int, bool) {
+	goals, err := readGoals()
+	if err != nil {
+		return 0, false
+	}
+	goal, ok := goals[statsDay(now).Format(goalDateFormat)]
+	return goal, ok
+}
+
+// saveDayGoal records goal as the goal for the day of now.
+func saveDayGoal(now time.Time, goal int) error {
+	goals, err := readGoals()
+	if err != nil {
+		return err
+	}
+	goals[statsDay(now).Format(goalDateFormat)] = goal
+
+	data, err := json.MarshalIndent(goalsFile{Version: goalsVersion, Goals: goals}, "", "  ")
+	if err != nil {
+		return err
+	}
+	path, err := goalsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// goalDays is how many days back goalDaysMet looks.
+const goalDays = 7
+
+// goalDaysMet counts the goalDays days before the day of now that had a
+// goal, the one set for the day in goals or else fallback, and how many of
+// them met it.
+func goalDaysMet(records []sessionRecord, goals map[string]int, fallback int, now time.Time) (met, days int) {
+	counts := make(map[string]int)
+	for _, record := range records {
+		if record.completedPomodoro() {
+			counts[statsDay(record.CompletedAt).Format(goalDateFormat)]++
+		}
+	}
+
+	for i := 1; i <= goalDays; i++ {
+		day := statsDay(now).AddDate(0, 0, -i).Format(goalDateFormat)
+		goal, ok := goals[day]
+		if !ok {
+			goal = fallback
+		}
+		if goal <= 0 {
+			continue
+		}
+		days++
+		if counts[day] >= goal {
+			met++
+		}
+	}
+	return met, days
+}
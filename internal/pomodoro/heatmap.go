@@ -0,0 +1,95 @@
+package pomodoro
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// heatmapLevels are the cells of the heatmap from no pomodoros to the
+// busiest day. The glyphs get denser as well as greener, so the levels can
+// still be told apart without color.
+var heatmapLevels = []string{
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#3A3A3A")).Render("·"),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#0E4429")).Render("░"),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#006D32")).Render("▒"),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#26A641")).Render("▓"),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#39D353")).Render("█"),
+}
+
+// Heatmap prints the calendar of the last year's pomodoros to w, counting
+// the sessions the stats in the app do.
+func Heatmap(w io.Writer, cfg Config) error {
+	records, err := readHistory()
+	if err != nil {
+		return err
+	}
+	return printHeatmap(w, cfg.counted(records), time.Now(), 52)
+}
+
+// printHeatmap writes a calendar of the pomodoros completed each day over
+// the last weeks weeks up to now, one column per week from Monday to Sunday
+// and the oldest week on the left.
+func printHeatmap(w io.Writer, records []sessionRecord, now time.Time, weeks int) error {
+	now = statsDay(now)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	start := monday.AddDate(0, 0, -7*(weeks-1))
+
+	counts := make(map[time.Time]int)
+	busiest, total := 0, 0
+	for _, record := range records {
+		if !record.completedPomodoro() {
+			continue
+		}
+		t := statsDay(record.CompletedAt)
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, now.Location())
+		if day.Before(start) || day.After(today) {
+			continue
+		}
+		counts[day]++
+		busiest = max(busiest, counts[day])
+		total++
+	}
+
+	// Label the week each month starts in, where it does not run into the
+	// previous label.
+	labels := []rune(strings.Repeat(" ", 4+weeks))
+	free := 4
+	for week := 0; week < weeks; week++ {
+		first, col := start.AddDate(0, 0, 7*week), 4+week
+		if (week == 0 || first.Day() <= 7) && col >= free && col+3 <= len(labels) {
+			copy(labels[col:], []rune(first.Format("Jan")))
+			free = col + 4
+		}
+	}
+	if _, err := fmt.Fprintln(w, strings.TrimRight(string(labels), " ")); err != nil {
+		return err
+	}
+
+	for weekday := 0; weekday < 7; weekday++ {
+		var row strings.Builder
+		row.WriteString(monday.AddDate(0, 0, weekday).Format("Mon") + " ")
+		for week := 0; week < weeks; week++ {
+			day := start.AddDate(0, 0, 7*week+weekday)
+			if day.After(today) {
+				break
+			}
+			level := 0
+			if count := counts[day]; count > 0 {
+				level = int(math.Ceil(float64(count) / float64(busiest) * float64(len(heatmapLevels)-1)))
+			}
+			row.WriteString(heatmapLevels[level])
+		}
+		if _, err := fmt.Fprintln(w, row.String()); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d pomodoros, most in a day: %d\n", total, busiest)
+	return err
+}
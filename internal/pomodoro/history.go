@@ -0,0 +1,265 @@
+package pomodoro
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// sessionRecord is a single finished session in the history log. Timestamps
+// are truncated to whole seconds and kept in local time, so encoding/json
+// writes them as RFC3339 with the timezone offset, e.g.
+// "2024-03-01T09:25:00+01:00".
+type sessionRecord struct {
+	// Version is the historyVersion the record was written with.
+	Version         int         `json:"version"`
+	Type            SessionKind `json:"type"`
+	Name            string      `json:"name"`
+	DurationSeconds int64       `json:"duration_seconds"`
+	StartedAt       time.Time   `json:"started_at"`
+	CompletedAt     time.Time   `json:"completed_at"`
+	// ElapsedSeconds is the wall time the session actually ran for, pauses
+	// aside, next to the planned DurationSeconds. It is zero in records from
+	// before version 3, where it is unknown.
+	ElapsedSeconds int64 `json:"elapsed_seconds,omitempty"`
+	// Abandoned marks a session reset before it completed, with the reason
+	// given for it, if any. DurationSeconds is the time it ran for.
+	Abandoned bool   `json:"abandoned,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	// Skipped marks an abandoned session that was skipped with s to move on
+	// to the next one, such as a break cut short.
+	Skipped bool `json:"skipped,omitempty"`
+	// NotClean flags a session paused for longer than -clean-only allows.
+	NotClean bool `json:"not_clean,omitempty"`
+	// Task is the todo.txt task the session was for, if any.
+	Task string `json:"task,omitempty"`
+	// Note is what the user wrote down after completing the session, if
+	// anything.
+	Note string `json:"note,omitempty"`
+	// Profile tags the session with the profile it was run under, if any.
+	Profile string `json:"profile,omitempty"`
+	// Pauses is only filled in when verbose logging is enabled.
+	Pauses []pauseInterval `json:"pauses,omitempty"`
+}
+
+// completedPomodoro reports whether the record is of a pomodoro that ran to
+// completion.
+func (r sessionRecord) completedPomodoro() bool {
+	return r.Type == Work && !r.Abandoned
+}
+
+// skippedBreak reports whether the record is of a break that was skipped.
+func (r sessionRecord) skippedBreak() bool {
+	return r.Type != Work && r.Skipped
+}
+
+// pauseInterval is a stretch of a session spent paused. ResumedAt is zero
+// while the session is still paused.
+type pauseInterval struct {
+	PausedAt  time.Time `json:"paused_at"`
+	ResumedAt time.Time `json:"resumed_at"`
+}
+
+// historyVersion is the current format of history records. Version 1
+// records were written before the field existed and read back as 0.
+const historyVersion = 3
+
+// migrateRecord brings a record read from the log up to historyVersion.
+func migrateRecord(record *sessionRecord) {
+	switch record.Version {
+	case 0, 1:
+		// Version 2 only added the version field itself.
+		fallthrough
+	case 2:
+		// Version 3 added ElapsedSeconds, which older records cannot know
+		// and leave at zero.
+		record.Version = 3
+	}
+}
+
+func historyPath() (string, error) {
+	dir, err := dataHome()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "pomodoro", "history.jsonl"), nil
+}
+
+// logSession appends record to the history log, creating it if needed.
+func logSession(record sessionRecord) error {
+	path, err := historyPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if HistoryBackups {
+		if err := backupHistory(time.Now()); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	record.Version = historyVersion
+	record.StartedAt = record.StartedAt.Local().Truncate(time.Second)
+	record.CompletedAt = record.CompletedAt.Local().Truncate(time.Second)
+	pauses := make([]pauseInterval, len(record.Pauses))
+	for i, p := range record.Pauses {
+		pauses[i] = pauseInterval{
+			PausedAt:  p.PausedAt.Local().Truncate(time.Second),
+			ResumedAt: p.ResumedAt.Local().Truncate(time.Second),
+		}
+	}
+	record.Pauses = pauses
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// errNotLastRecord is returned by removeLastRecord when the log ends in
+// another record.
+var errNotLastRecord = errors.New("record is not the last one in the history log")
+
+// removeLastRecord cuts record off the end of the history log. It refuses to
+// touch the log unless its last line is that record, so a record another
+// instance appended since is never lost.
+func removeLastRecord(record sessionRecord) error {
+	path, err := historyPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	body := bytes.TrimRight(data, "\n")
+	start := bytes.LastIndexByte(body, '\n') + 1
+	var last sessionRecord
+	if err := json.Unmarshal(body[start:], &last); err != nil {
+		return err
+	}
+	if last.Type != record.Type || last.CompletedAt.Unix() != record.CompletedAt.Unix() {
+		return errNotLastRecord
+	}
+
+	return os.Truncate(path, int64(start))
+}
+
+// readHistory returns every record in the history log.
+func readHistory() ([]sessionRecord, error) {
+	path, err := historyPath()
+	if err != nil {
+		return nil, err
+	}
+	return readHistoryFile(path)
+}
+
+// readHistoryFile returns every record in the history log at path. A missing
+// log is empty and lines that fail to parse are skipped.
+func readHistoryFile(path string) ([]sessionRecord, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []sessionRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record sessionRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		migrateRecord(&record)
+		records = append(records, record)
+	}
+
+	return records, scanner.Err()
+}
+
+// DayStartHour is the local hour the day rolls over at for the daily
+// totals, goals and calendar, so a session after midnight can still count to
+// the night before.
+var DayStartHour Hour
+
+// statsDay returns t in local time moved back by DayStartHour, so its date is
+// the day t counts to.
+func statsDay(t time.Time) time.Time {
+	return t.Local().Add(-time.Duration(DayStartHour) * time.Hour)
+}
+
+// sameDay reports whether a and b count to the same day.
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := statsDay(a).Date()
+	by, bm, bd := statsDay(b).Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// filterProfile returns the records tagged with profile.
+func filterProfile(records []sessionRecord, profile string) []sessionRecord {
+	var filtered []sessionRecord
+	for _, record := range records {
+		if record.Profile == profile {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
+}
+
+// filterClean drops the records flagged as not clean.
+func filterClean(records []sessionRecord) []sessionRecord {
+	var filtered []sessionRecord
+	for _, record := range records {
+		if !record.NotClean {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
+}
+
+// counted keeps the records the stats in the app count, those of the
+// current profile with ProfileStats and the clean ones with CleanOnly.
+func (c *Config) counted(records []sessionRecord) []sessionRecord {
+	if c.ProfileStats {
+		records = filterProfile(records, c.Profile)
+	}
+	if c.CleanOnly {
+		records = filterClean(records)
+	}
+	return records
+}
+
+// todayTotals counts the pomodoros completed on the day of now and the focus
+// time they add up to.
+func todayTotals(records []sessionRecord, now time.Time) (int, time.Duration) {
+	count, focus := 0, time.Duration(0)
+	for _, record := range records {
+		if record.completedPomodoro() && sameDay(record.CompletedAt, now) {
+			count++
+			focus += time.Duration(record.DurationSeconds) * time.Second
+		}
+	}
+	return count, focus
+}
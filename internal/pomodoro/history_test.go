@@ -0,0 +1,173 @@
+package pomodoro
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLogSessionRoundTrip(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	zone := time.FixedZone("CET", 60*60)
+	started := time.Date(2024, 3, 1, 9, 0, 0, 500, zone)
+	record := sessionRecord{
+		Type:            "pomodoro",
+		DurationSeconds: 25 * 60,
+		StartedAt:       started,
+		CompletedAt:     started.Add(25 * time.Minute),
+	}
+	if err := logSession(record); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := historyPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	if len(lines) != 1 {
+		t.Fatalf("%s has %d lines, want 1", filepath.Base(path), len(lines))
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(lines[0], &fields); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]time.Time{"started_at": record.StartedAt, "completed_at": record.CompletedAt} {
+		text, _ := fields[name].(string)
+		parsed, err := time.Parse(time.RFC3339, text)
+		if err != nil {
+			t.Errorf("%s = %q, not RFC3339: %v", name, text, err)
+			continue
+		}
+		if !parsed.Equal(want.Truncate(time.Second)) {
+			t.Errorf("%s = %s, want %s", name, parsed, want.Truncate(time.Second))
+		}
+		if want := want.Local().Format("-07:00"); parsed.Format("-07:00") != want {
+			t.Errorf("%s has offset %s, want the local %s", name, parsed.Format("-07:00"), want)
+		}
+	}
+
+	var decoded sessionRecord
+	if err := json.Unmarshal(lines[0], &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Type != record.Type || decoded.DurationSeconds != record.DurationSeconds {
+		t.Errorf("decoded %+v, want %+v", decoded, record)
+	}
+	if !decoded.StartedAt.Equal(record.StartedAt.Truncate(time.Second)) || !decoded.CompletedAt.Equal(record.CompletedAt.Truncate(time.Second)) {
+		t.Errorf("decoded times %s to %s, want %s to %s", decoded.StartedAt, decoded.CompletedAt, record.StartedAt, record.CompletedAt)
+	}
+}
+
+func TestReadHistoryMigratesOldRecords(t *testing.T) {
+	// A record of each older version, as they were written to the log.
+	lines := map[string]string{
+		"v0": `{"type":"pomodoro","name":"Pomodoro","duration_seconds":1500,"started_at":"2024-03-01T09:00:00+01:00","completed_at":"2024-03-01T09:25:00+01:00"}`,
+		"v1": `{"version":1,"type":"pomodoro","name":"Pomodoro","duration_seconds":1500,"started_at":"2024-03-01T09:00:00+01:00","completed_at":"2024-03-01T09:25:00+01:00"}`,
+		"v2": `{"version":2,"type":"pomodoro","name":"Pomodoro","duration_seconds":1500,"started_at":"2024-03-01T09:00:00+01:00","completed_at":"2024-03-01T09:25:00+01:00","pauses":[{"paused_at":"2024-03-01T09:10:00+01:00","resumed_at":"2024-03-01T09:12:00+01:00"}]}`,
+	}
+	for name, line := range lines {
+		t.Run(name, func(t *testing.T) {
+			isolate(t)
+			path, err := historyPath()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(line+"\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			records, err := readHistory()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != 1 {
+				t.Fatalf("read %d records, want 1", len(records))
+			}
+			record := records[0]
+			if record.Version != historyVersion {
+				t.Errorf("Version = %d, want %d", record.Version, historyVersion)
+			}
+			if record.ElapsedSeconds != 0 {
+				t.Errorf("ElapsedSeconds = %d, want 0 for a record that can't know it", record.ElapsedSeconds)
+			}
+			if record.DurationSeconds != 1500 || !record.completedPomodoro() {
+				t.Errorf("read %+v, want a completed 25m pomodoro", record)
+			}
+		})
+	}
+}
+
+func TestReadGoalsMigratesOldFile(t *testing.T) {
+	files := map[string]string{
+		"v1": `{"2024-03-01": 6, "2024-03-02": 8}`,
+		"v2": `{"version": 2, "goals": {"2024-03-01": 6, "2024-03-02": 8}}`,
+	}
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			isolate(t)
+			path, err := goalsPath()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			goals, err := readGoals()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(goals) != 2 || goals["2024-03-01"] != 6 || goals["2024-03-02"] != 8 {
+				t.Errorf("goals = %v, want 6 on 2024-03-01 and 8 on 2024-03-02", goals)
+			}
+		})
+	}
+}
+
+func TestDayBoundary(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 3, day, hour, minute, 0, 0, time.Local)
+	}
+	tests := []struct {
+		name     string
+		dayStart Hour
+		a, b     time.Time
+		wantSame bool
+		wantDay  string // the day a counts to
+	}{
+		{"midnight, last minute of the day", 0, at(1, 23, 59), at(1, 0, 0), true, "2024-03-01"},
+		{"midnight, across it", 0, at(1, 23, 59), at(2, 0, 0), false, "2024-03-01"},
+		{"4am, before it", 4, at(2, 3, 59), at(1, 22, 0), true, "2024-03-01"},
+		{"4am, across it", 4, at(2, 3, 59), at(2, 4, 0), false, "2024-03-01"},
+		{"4am, on it", 4, at(2, 4, 0), at(2, 23, 59), true, "2024-03-02"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := DayStartHour
+			DayStartHour = tt.dayStart
+			t.Cleanup(func() { DayStartHour = saved })
+
+			if got := sameDay(tt.a, tt.b); got != tt.wantSame {
+				t.Errorf("sameDay(%s, %s) = %t, want %t", tt.a.Format("Jan 2 15:04"), tt.b.Format("Jan 2 15:04"), got, tt.wantSame)
+			}
+			if got := statsDay(tt.a).Format(goalDateFormat); got != tt.wantDay {
+				t.Errorf("%s counts to %s, want %s", tt.a.Format("Jan 2 15:04"), got, tt.wantDay)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package pomodoro
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// hookEvent is the event a change of a session of kind fires, if any.
+func hookEvent(kind SessionKind, change timerChange) (HookEvent, bool) {
+	switch change {
+	case timerStarted, timerRestarted:
+		if kind == Work {
+			return EventSessionStart, true
+		}
+		return EventBreakStart, true
+	case timerCompleted:
+		if kind == Work {
+			return EventSessionEnd, true
+		}
+		return EventBreakEnd, true
+	case timerPaused:
+		return EventPause, true
+	case timerResumed:
+		return EventResume, true
+	}
+	return "", false
+}
+
+// runHooks runs the hook commands for the events fired by the timers
+// changing from prev to m.
+func (m Model) runHooks(prev Model) tea.Cmd {
+	if len(m.Hooks) == 0 {
+		return nil
+	}
+
+	changes := m.changes(prev)
+	var cmds []tea.Cmd
+	for i, session := range m.Tabs {
+		for _, change := range changes[i] {
+			event, ok := hookEvent(session.Kind, change)
+			if !ok {
+				continue
+			}
+			if command, ok := m.Hooks[event]; ok {
+				// A session is run for its tab's duration with the time + and
+				// - added, which a completed timer has already been reset from.
+				session.Duration = m.getDurationByIndex(i)
+				if change == timerCompleted {
+					session.Duration = prev.getDurationByIndex(i)
+				}
+				cmds = append(cmds, runHook(command, event, session))
+			}
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// runHook runs command in the shell with the event and the session it is
+// about in POMODORO_EVENT, POMODORO_NAME, POMODORO_TYPE and
+// POMODORO_DURATION_SECONDS. Its output is discarded, so it cannot mess up
+// the screen.
+func runHook(command string, event HookEvent, session Session) tea.Cmd {
+	return func() tea.Msg {
+		cmd := exec.Command("sh", "-c", command)
+		if runtime.GOOS == "windows" {
+			cmd = exec.Command("cmd", "/C", command)
+		}
+		cmd.Env = append(os.Environ(),
+			"POMODORO_EVENT="+string(event),
+			"POMODORO_NAME="+session.Name,
+			"POMODORO_TYPE="+string(session.Kind),
+			"POMODORO_DURATION_SECONDS="+strconv.Itoa(int(session.Duration.Seconds())),
+		)
+		cmd.Run()
+		return nil
+	}
+}
@@ -0,0 +1,45 @@
+package pomodoro
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestHookDurationIncludesAdjustment(t *testing.T) {
+	dir := isolate(t)
+	out := filepath.Join(dir, "duration")
+	cfg := defaultConfig()
+	cfg.Hooks = Hooks{EventSessionEnd: "echo $POMODORO_DURATION_SECONDS > " + out}
+	m := NewModel(cfg, &fakeNotifier{})
+	mode := m.workIndex()
+	finishTimer(&m, mode)
+	m.Timers[mode].Extra = adjustStep
+
+	prev := m
+	prev.Timers = append([]timer(nil), m.Timers...)
+	m.handleCompletion(mode)
+	cmd := m.runHooks(prev)
+	if cmd == nil {
+		t.Fatal("no hook ran for the completed pomodoro")
+	}
+	if batch, ok := cmd().(tea.BatchMsg); ok {
+		for _, cmd := range batch {
+			cmd()
+		}
+	}
+
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strconv.Itoa(int((25*time.Minute + adjustStep).Seconds()))
+	if strings.TrimSpace(string(got)) != want {
+		t.Errorf("POMODORO_DURATION_SECONDS = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,300 @@
+package pomodoro
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// language is the catalog tr looks strings up in.
+var language = "en"
+
+// catalog holds the translations of the built-in strings, keyed by language
+// and then by the English text. English is the source language and needs no
+// entry; any missing key falls back to it.
+var catalog = map[string]map[string]string{
+	"pl": {
+		"Pomodoro":                           "Pomodoro",
+		"Short break":                        "Krótka przerwa",
+		"Long break":                         "Długa przerwa",
+		"Pomodoro done":                      "Pomodoro zakończone",
+		"Next: %s":                           "Dalej: %s",
+		"Next: %s (%s of %d)":                "Dalej: %s (%s z %d)",
+		"Viewing %s, %s is still running":    "Widok: %s, %s wciąż trwa",
+		"Viewing %s, %s is still paused":     "Widok: %s, %s jest wstrzymane",
+		"%s %s - terminal too small":         "%s %s - za mały terminal",
+		"Focus score: %d":                    "Wynik skupienia: %d",
+		"System slept - session adjusted":    "System był uśpiony - sesja skorygowana",
+		"System slept - session paused":      "System był uśpiony - sesja wstrzymana",
+		"Wrap up - break in %s":              "Kończ - przerwa za %s",
+		"Discard %s and start %s? (y/n)":     "Porzucić %s i zacząć %s? (y/n)",
+		"Daily summary":                      "Podsumowanie dnia",
+		"Pomodoros: %d, focus: %s":           "Pomodoro: %d, skupienie: %s",
+		"%s started":                         "%s - start",
+		"%s paused":                          "%s - pauza",
+		"%s resumed":                         "%s - wznowienie",
+		"%s restarted":                       "%s - restart",
+		"%s reset":                           "%s - reset",
+		"%s completed":                       "%s - koniec",
+		"%s stopped with %s left":            "%s - przerwane, zostało %s",
+		"%s is running on another tab":       "%s trwa na innej karcie",
+		"%s is paused on another tab":        "%s jest wstrzymane na innej karcie",
+		"Locked - press L to unlock":         "Zablokowane - naciśnij L, aby odblokować",
+		"Lifetime: %s pomodoros":             "Łącznie: %s pomodoro",
+		"✅ %s complete!":                     "✅ %s - gotowe!",
+		"Goal: %d/%d":                        "Cel: %d/%d",
+		"Why reset?":                         "Dlaczego reset?",
+		"Next session can start in %s":       "Następna sesja może zacząć się za %s",
+		"Nothing to undo":                    "Nie ma czego cofnąć",
+		"Undo the %s completed at %s? (y/n)": "Cofnąć %s zakończone o %s? (y/n)",
+		"Can't undo: %v":                     "Nie można cofnąć: %v",
+		"Config reloaded":                    "Ustawienia wczytane ponownie",
+		"Config not reloaded: %s":            "Ustawienia nie wczytane: %s",
+		"interrupted by...":                  "przerwane przez...",
+		"enter to save, esc to skip":         "enter zapisuje, esc pomija",
+		"Today's goal: %s_ (enter to save, esc to cancel)": "Dzisiejszy cel: %s_ (enter zapisuje, esc anuluje)",
+		"%s timer: %s remaining, %s":                       "%s: zostało %s, %s",
+		"running":                                          "trwa",
+		"paused":                                           "wstrzymane",
+		"not started":                                      "nie rozpoczęte",
+		"%d hour":                                          "%d godz.",
+		"%d hours":                                         "%d godz.",
+		"%d minute":                                        "%d min",
+		"%d minutes":                                       "%d min",
+		"%d second":                                        "%d s",
+		"%d seconds":                                       "%d s",
+		"%d more to hit today's goal":                      "jeszcze %d do dzisiejszego celu",
+		"goal met 🎉":                                       "cel osiągnięty 🎉",
+		"Distraction:":                                     "Rozproszenie:",
+		"what came up?":                                    "co przyszło do głowy?",
+		"enter to save, esc to cancel":                     "enter zapisuje, esc anuluje",
+		"Noted":                                            "Zapisano",
+		"space start":                                      "spacja start",
+		"space pause":                                      "spacja pauza",
+		"space resume":                                     "spacja wznów",
+		"r reset":                                          "r reset",
+		"←/→ switch":                                       "←/→ zmiana",
+		"? hide":                                           "? ukryj",
+		"q quit":                                           "q wyjście",
+		"Note:":                                            "Notatka:",
+		"what did you get done?":                           "co udało się zrobić?",
+		"The history log is damaged - restore the backup from %s? (y/n)": "Historia jest uszkodzona - przywrócić kopię z %s? (y/n)",
+		"Restore failed: %v":       "Przywracanie nie powiodło się: %v",
+		"Long break in %s":         "Długa przerwa za %s",
+		"%s is turned off":         "%s jest wyłączone",
+		"Task: %s":                 "Zadanie: %s",
+		"Can't read the tasks: %v": "Nie można odczytać zadań: %v",
+		"No pending tasks":         "Brak zadań do zrobienia",
+		"enter to pick, 0-9 to estimate, backspace to clear, esc to cancel": "enter wybiera, 0-9 szacuje, backspace czyści, esc anuluje",
+		"Pick a task, %d of %d: %s":                                         "Wybierz zadanie, %d z %d: %s",
+		"%s stopped after running for %s":                                   "%s zatrzymano po %s",
+		"%s ready":                                                          "%s gotowe",
+		"Notification failed: %v":                                           "Powiadomienie nie powiodło się: %v",
+		"Can't watch for %s: %v":                                            "Nie można obserwować %s: %v",
+		"Paused while %s is not running":                                    "Wstrzymano, dopóki %s nie działa",
+		"No snoozes left":                                                   "Nie można już drzemać",
+		"Snoozed for %s":                                                    "Drzemka na %s",
+		"met %d of the last %d days":                                        "osiągnięty w %d z ostatnich %d dni",
+		"Rest a bit longer (%s left)":                                       "Odpocznij jeszcze chwilę (zostało %s)",
+		"Break idea: %s":                                                    "Pomysł na przerwę: %s",
+		"No notification to replay":                                         "Brak powiadomienia do powtórzenia",
+		"%s left":                                                           "zostało %s",
+		"%s in":                                                             "minęło %s",
+		"Done!":                                                             "Gotowe!",
+		"Sessions start one after another":                                  "Sesje zaczynają się jedna po drugiej",
+		"Sessions wait for space to start":                                  "Sesje czekają na spację",
+		"%s skipped":                                                        "Pominięto: %s",
+		"Skip %s? (y/n)":                                                    "Pominąć %s? (y/n)",
+		"Notifications and sounds muted":                                    "Powiadomienia i dźwięki wyciszone",
+		"Notifications muted":                                               "Powiadomienia wyciszone",
+		"Sounds muted":                                                      "Dźwięki wyciszone",
+		"Pomodoro count reset":                                              "Licznik pomodoro wyzerowany",
+		"Strict mode - the pomodoro runs to the end":                        "Tryb ścisły - pomodoro trwa do końca",
+		"strict":                            "tryb ścisły",
+		"%s is now %s long":                 "%s trwa teraz %s",
+		"Stats":                             "Statystyki",
+		"%d pomodoros, %d minutes of focus": "%d pomodoro, %d min skupienia",
+		"Work: %d, Short: %d, Long: %d":     "Praca: %d, krótkie: %d, długie: %d",
+		"Breaks: %d taken, %d skipped":      "Przerwy: %d wzięte, %d pominięte",
+		"Goal met %d of the last %d days":   "Cel osiągnięty w %d z ostatnich %d dni",
+		"No sessions today":                 "Dziś jeszcze bez sesji",
+		"skipped":                           "pominięta",
+		"abandoned":                         "porzucona",
+		"%d interruption":                   "%d przerwanie",
+		"%d interruptions":                  "%d przerwań",
+		"paused %s":                         "pauza %s",
+		"Can't read the history: %v":        "Nie można odczytać historii: %v",
+		"Can't write the history: %v":       "Nie można zapisać historii: %v",
+		"Quit? (y/n)":                       "Zakończyć? (y/n)",
+		"Goal reached!":                     "Cel osiągnięty!",
+		"%d pomodoros today":                "%d pomodoro dzisiaj",
+		"Start cancelled":                   "Start anulowany",
+		"%s starts in %d…":                  "%s zaczyna się za %d…",
+		"space to cancel":                   "spacja, aby anulować",
+		"space cancel":                      "spacja anuluj",
+		// The thousands separator of groupDigits.
+		",": "\u00a0",
+	},
+	"de": {
+		"Pomodoro":                           "Pomodoro",
+		"Short break":                        "Kurze Pause",
+		"Long break":                         "Lange Pause",
+		"Pomodoro done":                      "Pomodoro fertig",
+		"Next: %s":                           "Als Nächstes: %s",
+		"Next: %s (%s of %d)":                "Als Nächstes: %s (%s von %d)",
+		"Viewing %s, %s is still running":    "Ansicht: %s, %s läuft noch",
+		"Viewing %s, %s is still paused":     "Ansicht: %s, %s ist pausiert",
+		"%s %s - terminal too small":         "%s %s - Terminal zu klein",
+		"Focus score: %d":                    "Fokuswert: %d",
+		"System slept - session adjusted":    "System war im Ruhezustand - Sitzung angepasst",
+		"System slept - session paused":      "System war im Ruhezustand - Sitzung pausiert",
+		"Wrap up - break in %s":              "Zum Ende kommen - Pause in %s",
+		"Discard %s and start %s? (y/n)":     "%s verwerfen und %s starten? (y/n)",
+		"Daily summary":                      "Tageszusammenfassung",
+		"Pomodoros: %d, focus: %s":           "Pomodoros: %d, Fokus: %s",
+		"%s started":                         "%s gestartet",
+		"%s paused":                          "%s pausiert",
+		"%s resumed":                         "%s fortgesetzt",
+		"%s restarted":                       "%s neu gestartet",
+		"%s reset":                           "%s zurückgesetzt",
+		"%s completed":                       "%s abgeschlossen",
+		"%s stopped with %s left":            "%s beendet, %s übrig",
+		"%s is running on another tab":       "%s läuft auf einem anderen Tab",
+		"%s is paused on another tab":        "%s ist auf einem anderen Tab pausiert",
+		"Locked - press L to unlock":         "Gesperrt - L zum Entsperren drücken",
+		"Lifetime: %s pomodoros":             "Insgesamt: %s Pomodoros",
+		"✅ %s complete!":                     "✅ %s fertig!",
+		"Goal: %d/%d":                        "Ziel: %d/%d",
+		"Why reset?":                         "Warum zurücksetzen?",
+		"Next session can start in %s":       "Nächste Sitzung kann in %s starten",
+		"Nothing to undo":                    "Nichts rückgängig zu machen",
+		"Undo the %s completed at %s? (y/n)": "%s von %s rückgängig machen? (y/n)",
+		"Can't undo: %v":                     "Rückgängig nicht möglich: %v",
+		"Config reloaded":                    "Einstellungen neu geladen",
+		"Config not reloaded: %s":            "Einstellungen nicht geladen: %s",
+		"interrupted by...":                  "unterbrochen durch...",
+		"enter to save, esc to skip":         "Enter speichert, Esc überspringt",
+		"Today's goal: %s_ (enter to save, esc to cancel)": "Heutiges Ziel: %s_ (Enter speichert, Esc bricht ab)",
+		"%s timer: %s remaining, %s":                       "%s-Timer: noch %s, %s",
+		"running":                                          "läuft",
+		"paused":                                           "pausiert",
+		"not started":                                      "nicht gestartet",
+		"%d hour":                                          "%d Stunde",
+		"%d hours":                                         "%d Stunden",
+		"%d minute":                                        "%d Minute",
+		"%d minutes":                                       "%d Minuten",
+		"%d second":                                        "%d Sekunde",
+		"%d seconds":                                       "%d Sekunden",
+		"%d more to hit today's goal":                      "noch %d bis zum heutigen Ziel",
+		"goal met 🎉":                                       "Ziel erreicht 🎉",
+		"Distraction:":                                     "Ablenkung:",
+		"what came up?":                                    "was ist dir eingefallen?",
+		"enter to save, esc to cancel":                     "Enter speichert, Esc bricht ab",
+		"Noted":                                            "Notiert",
+		"space start":                                      "Leertaste Start",
+		"space pause":                                      "Leertaste Pause",
+		"space resume":                                     "Leertaste Fortsetzen",
+		"r reset":                                          "r Zurücksetzen",
+		"←/→ switch":                                       "←/→ Wechseln",
+		"? hide":                                           "? Ausblenden",
+		"q quit":                                           "q Beenden",
+		"Note:":                                            "Notiz:",
+		"what did you get done?":                           "was hast du geschafft?",
+		"The history log is damaged - restore the backup from %s? (y/n)": "Der Verlauf ist beschädigt - Sicherung vom %s wiederherstellen? (y/n)",
+		"Restore failed: %v":       "Wiederherstellung fehlgeschlagen: %v",
+		"Long break in %s":         "Lange Pause in %s",
+		"%s is turned off":         "%s ist ausgeschaltet",
+		"Task: %s":                 "Aufgabe: %s",
+		"Can't read the tasks: %v": "Aufgaben nicht lesbar: %v",
+		"No pending tasks":         "Keine offenen Aufgaben",
+		"enter to pick, 0-9 to estimate, backspace to clear, esc to cancel": "Enter wählt, 0-9 schätzt, Rücktaste leert, Esc bricht ab",
+		"Pick a task, %d of %d: %s":                                         "Aufgabe wählen, %d von %d: %s",
+		"%s stopped after running for %s":                                   "%s nach %s angehalten",
+		"%s ready":                                                          "%s bereit",
+		"Notification failed: %v":                                           "Benachrichtigung fehlgeschlagen: %v",
+		"Can't watch for %s: %v":                                            "%s kann nicht beobachtet werden: %v",
+		"Paused while %s is not running":                                    "Pausiert, solange %s nicht läuft",
+		"No snoozes left":                                                   "Keine Schlummerrunden mehr",
+		"Snoozed for %s":                                                    "Schlummern für %s",
+		"met %d of the last %d days":                                        "an %d der letzten %d Tage erreicht",
+		"Rest a bit longer (%s left)":                                       "Ruh dich noch etwas aus (noch %s)",
+		"Break idea: %s":                                                    "Idee für die Pause: %s",
+		"No notification to replay":                                         "Keine Benachrichtigung zum Wiederholen",
+		"%s left":                                                           "noch %s",
+		"%s in":                                                             "schon %s",
+		"Done!":                                                             "Fertig!",
+		"Sessions start one after another":                                  "Sitzungen starten nacheinander",
+		"Sessions wait for space to start":                                  "Sitzungen warten auf die Leertaste",
+		"%s skipped":                                                        "%s übersprungen",
+		"Skip %s? (y/n)":                                                    "%s überspringen? (y/n)",
+		"Notifications and sounds muted":                                    "Benachrichtigungen und Töne stumm",
+		"Notifications muted":                                               "Benachrichtigungen stumm",
+		"Sounds muted":                                                      "Töne stumm",
+		"Pomodoro count reset":                                              "Pomodoro-Zähler zurückgesetzt",
+		"Strict mode - the pomodoro runs to the end":                        "Strenger Modus - das Pomodoro läuft bis zum Ende",
+		"strict":                            "streng",
+		"%s is now %s long":                 "%s dauert jetzt %s",
+		"Stats":                             "Statistik",
+		"%d pomodoros, %d minutes of focus": "%d Pomodoros, %d Minuten Fokus",
+		"Work: %d, Short: %d, Long: %d":     "Arbeit: %d, kurz: %d, lang: %d",
+		"Breaks: %d taken, %d skipped":      "Pausen: %d gemacht, %d übersprungen",
+		"Goal met %d of the last %d days":   "Ziel an %d der letzten %d Tage erreicht",
+		"No sessions today":                 "Heute noch keine Sitzungen",
+		"skipped":                           "übersprungen",
+		"abandoned":                         "abgebrochen",
+		"%d interruption":                   "%d Unterbrechung",
+		"%d interruptions":                  "%d Unterbrechungen",
+		"paused %s":                         "%s pausiert",
+		"Can't read the history: %v":        "Verlauf nicht lesbar: %v",
+		"Can't write the history: %v":       "Verlauf nicht schreibbar: %v",
+		"Quit? (y/n)":                       "Beenden? (y/n)",
+		"Goal reached!":                     "Ziel erreicht!",
+		"%d pomodoros today":                "heute %d Pomodoros",
+		"Start cancelled":                   "Start abgebrochen",
+		"%s starts in %d…":                  "%s beginnt in %d…",
+		"space to cancel":                   "Leertaste zum Abbrechen",
+		"space cancel":                      "Leertaste Abbruch",
+		// The thousands separator of groupDigits.
+		",": ".",
+	},
+}
+
+// tr returns the translation of s in the current language, or s itself.
+func tr(s string) string {
+	if t, ok := catalog[language][s]; ok {
+		return t
+	}
+	return s
+}
+
+// trf is fmt.Sprintf over a translated format string.
+func trf(format string, a ...any) string {
+	return fmt.Sprintf(tr(format), a...)
+}
+
+// DetectLanguage reads the language code from the usual locale variables,
+// e.g. "pl" for LANG=pl_PL.UTF-8.
+func DetectLanguage() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			return normalizeLanguage(value)
+		}
+	}
+	return "en"
+}
+
+// SetLanguage sets the language of the UI and notifications from a language
+// code or locale, e.g. "pl" or "pl_PL.UTF-8".
+func SetLanguage(locale string) {
+	language = normalizeLanguage(locale)
+}
+
+func normalizeLanguage(locale string) string {
+	code, _, _ := strings.Cut(locale, ".")
+	code, _, _ = strings.Cut(code, "_")
+	code = strings.ToLower(code)
+	if code == "" || code == "c" || code == "posix" {
+		return "en"
+	}
+	return code
+}
@@ -0,0 +1,36 @@
+package pomodoro
+
+import (
+	"bytes"
+	_ "embed"
+	"os"
+	"path/filepath"
+)
+
+// iconPNG is the default notification icon, built into the binary so it is
+// found whatever directory the app runs from.
+//
+//go:embed assets/pomodoro.png
+var iconPNG []byte
+
+// embeddedIcon returns the path of iconPNG written out under the cache dir,
+// as the notification backends take an icon by path. It is only written
+// again when it differs, e.g. after an upgrade.
+func embeddedIcon() (string, error) {
+	dir, err := cacheHome()
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, "pomodoro", "pomodoro.png")
+	if data, err := os.ReadFile(path); err == nil && bytes.Equal(data, iconPNG) {
+		return path, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, iconPNG, 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,201 @@
+package pomodoro
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ImportHistory merges the session records in the CSV or JSON file at path
+// into the history log. Records whose completion time is already in the log,
+// or earlier in the file, are skipped. Nothing is written if any record in
+// the file is invalid.
+func ImportHistory(path string) (imported, skipped int, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	var records []sessionRecord
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		records, err = readCSVRecords(f)
+	} else {
+		records, err = readJSONRecords(f)
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", path, err)
+	}
+
+	existing, err := readHistory()
+	if err != nil {
+		return 0, 0, err
+	}
+	seen := make(map[int64]bool, len(existing))
+	for _, record := range existing {
+		seen[record.CompletedAt.Unix()] = true
+	}
+
+	for _, record := range records {
+		if seen[record.CompletedAt.Unix()] {
+			skipped++
+			continue
+		}
+		if err := logSession(record); err != nil {
+			return imported, skipped, err
+		}
+		seen[record.CompletedAt.Unix()] = true
+		imported++
+	}
+	return imported, skipped, nil
+}
+
+// readCSVRecords parses a CSV file with a header row naming the same columns
+// as the history log: type, duration_seconds and completed_at are required;
+// name, started_at, profile, task, abandoned, skipped and not_clean are
+// optional. A duration_minutes column, as -export-csv writes, can stand in
+// for duration_seconds.
+func readCSVRecords(r io.Reader) ([]sessionRecord, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{"type", "completed_at"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+	_, seconds := columns["duration_seconds"]
+	if _, minutes := columns["duration_minutes"]; !seconds && !minutes {
+		return nil, errors.New(`missing column "duration_seconds"`)
+	}
+	field := func(row []string, name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	var records []sessionRecord
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		record := sessionRecord{
+			Type:    SessionKind(field(row, "type")),
+			Name:    field(row, "name"),
+			Profile: field(row, "profile"),
+			Task:    field(row, "task"),
+		}
+		if seconds {
+			if record.DurationSeconds, err = strconv.ParseInt(field(row, "duration_seconds"), 10, 64); err != nil {
+				return nil, fmt.Errorf("line %d: invalid duration_seconds: %w", line, err)
+			}
+		} else {
+			minutes, err := strconv.ParseFloat(field(row, "duration_minutes"), 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid duration_minutes: %w", line, err)
+			}
+			record.DurationSeconds = int64(math.Round(minutes * 60))
+		}
+		for name, flag := range map[string]*bool{"abandoned": &record.Abandoned, "skipped": &record.Skipped, "not_clean": &record.NotClean} {
+			if value := field(row, name); value != "" {
+				if *flag, err = strconv.ParseBool(value); err != nil {
+					return nil, fmt.Errorf("line %d: invalid %s: %w", line, name, err)
+				}
+			}
+		}
+		if record.CompletedAt, err = time.Parse(time.RFC3339, field(row, "completed_at")); err != nil {
+			return nil, fmt.Errorf("line %d: invalid completed_at: %w", line, err)
+		}
+		if started := field(row, "started_at"); started != "" {
+			if record.StartedAt, err = time.Parse(time.RFC3339, started); err != nil {
+				return nil, fmt.Errorf("line %d: invalid started_at: %w", line, err)
+			}
+		}
+		if err := validateRecord(&record); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+}
+
+// readJSONRecords parses either a JSON array of records or one record per
+// line, as in the history log itself.
+func readJSONRecords(r io.Reader) ([]sessionRecord, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []sessionRecord
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		if err := json.Unmarshal(data, &records); err != nil {
+			return nil, err
+		}
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		for {
+			var record sessionRecord
+			if err := dec.Decode(&record); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("record %d: %w", len(records)+1, err)
+			}
+			records = append(records, record)
+		}
+	}
+
+	for i := range records {
+		if err := validateRecord(&records[i]); err != nil {
+			return nil, fmt.Errorf("record %d: %w", i+1, err)
+		}
+	}
+	return records, nil
+}
+
+// validateRecord checks an imported record and fills in a missing start
+// time from its completion time and duration.
+func validateRecord(record *sessionRecord) error {
+	switch record.Type {
+	case Work, ShortBreak, LongBreak:
+	default:
+		return fmt.Errorf("unknown type %q, want %q, %q or %q", record.Type, Work, ShortBreak, LongBreak)
+	}
+	if record.DurationSeconds < 0 {
+		return errors.New("negative duration_seconds")
+	}
+	if record.CompletedAt.IsZero() {
+		return errors.New("missing completed_at")
+	}
+	if record.StartedAt.IsZero() {
+		record.StartedAt = record.CompletedAt.Add(-time.Duration(record.DurationSeconds) * time.Second)
+	}
+	if record.StartedAt.After(record.CompletedAt) {
+		return errors.New("started_at is after completed_at")
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package pomodoro
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.Local)
+	records := []sessionRecord{
+		{
+			Type:            Work,
+			Name:            "Pomodoro",
+			DurationSeconds: 1500,
+			StartedAt:       start,
+			CompletedAt:     start.Add(25 * time.Minute),
+			Profile:         "coding",
+			Task:            "write the report",
+		},
+		{
+			Type:            ShortBreak,
+			Name:            "Short break",
+			DurationSeconds: 100,
+			StartedAt:       start.Add(25 * time.Minute),
+			CompletedAt:     start.Add(27 * time.Minute),
+			Abandoned:       true,
+			Skipped:         true,
+		},
+		{
+			Type:            Work,
+			Name:            "Pomodoro",
+			DurationSeconds: 1500,
+			StartedAt:       start.Add(30 * time.Minute),
+			CompletedAt:     start.Add(60 * time.Minute),
+			NotClean:        true,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeRecordsCSV(&buf, records); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readCSVRecords(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(records) {
+		t.Fatalf("read %d records back, want %d", len(got), len(records))
+	}
+	for i, want := range records {
+		g := got[i]
+		if !g.StartedAt.Equal(want.StartedAt) || !g.CompletedAt.Equal(want.CompletedAt) {
+			t.Errorf("record %d ran %s to %s, want %s to %s", i, g.StartedAt, g.CompletedAt, want.StartedAt, want.CompletedAt)
+		}
+		g.StartedAt, g.CompletedAt = want.StartedAt, want.CompletedAt
+		if !reflect.DeepEqual(g, want) {
+			t.Errorf("record %d = %+v, want %+v", i, g, want)
+		}
+	}
+}
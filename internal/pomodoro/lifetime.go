@@ -0,0 +1,106 @@
+package pomodoro
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// lifetimeCache remembers how many pomodoros the history log held up to
+// Offset bytes, so only records appended since have to be read. A cache of
+// another lifetimeCacheVersion is rebuilt.
+type lifetimeCache struct {
+	Version   int   `json:"version"`
+	Offset    int64 `json:"offset"`
+	Pomodoros int   `json:"pomodoros"`
+}
+
+const lifetimeCacheVersion = 2
+
+func lifetimeCachePath() (string, error) {
+	dir, err := cacheHome()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "pomodoro", "lifetime.json"), nil
+}
+
+// lifetimePomodoros counts every pomodoro in the history log, across all
+// profiles, and updates the cache it starts from.
+func lifetimePomodoros() (int, error) {
+	path, err := historyPath()
+	if err != nil {
+		return 0, err
+	}
+	cachePath, err := lifetimeCachePath()
+	if err != nil {
+		return 0, err
+	}
+
+	var cache lifetimeCache
+	if data, err := os.ReadFile(cachePath); err == nil {
+		json.Unmarshal(data, &cache)
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	// A log that shrank was replaced or edited, so count it from scratch.
+	if info, err := f.Stat(); err != nil {
+		return 0, err
+	} else if info.Size() < cache.Offset || cache.Version != lifetimeCacheVersion {
+		cache = lifetimeCache{Version: lifetimeCacheVersion}
+	}
+	if _, err := f.Seek(cache.Offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	// Only complete lines move the offset on; a line being written right
+	// now is counted next time.
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			break
+		}
+		cache.Offset += int64(len(line))
+
+		var record sessionRecord
+		if json.Unmarshal(line, &record) == nil && record.completedPomodoro() {
+			cache.Pomodoros++
+		}
+	}
+
+	if data, err := json.Marshal(cache); err == nil && os.MkdirAll(filepath.Dir(cachePath), 0o755) == nil {
+		os.WriteFile(cachePath, data, 0o644)
+	}
+	return cache.Pomodoros, nil
+}
+
+// groupDigits writes n with its thousands separated in the current
+// language, e.g. "1,204".
+func groupDigits(n int) string {
+	if n < 0 {
+		return "-" + groupDigits(-n)
+	}
+
+	digits, separator := strconv.Itoa(n), tr(",")
+	var grouped []byte
+	for i := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped = append(grouped, separator...)
+		}
+		grouped = append(grouped, digits[i])
+	}
+	return string(grouped)
+}
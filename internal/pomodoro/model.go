@@ -0,0 +1,1293 @@
+// Package pomodoro is the pomodoro timer: the Bubble Tea model of its tabs
+// and sessions, its config and the history log the sessions go to.
+package pomodoro
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type ProgressStatus string
+
+const (
+	Idle    ProgressStatus = "idle"
+	Paused  ProgressStatus = "paused"
+	Running ProgressStatus = "running"
+)
+
+var (
+	tabLines          = borderSets[BorderRounded]
+	inactiveTabBorder = tabBorderWithBottom(tabLines.Up, tabLines.Border.Bottom, tabLines.Up)
+	activeTabBorder   = tabBorderWithBottom(tabLines.CornerRight, " ", tabLines.CornerLeft)
+	docStyle          = lipgloss.NewStyle().Padding(1, 2, 1, 2)
+	theme             = themes[defaultTheme]
+	inactiveTabStyle  = lipgloss.NewStyle().Border(inactiveTabBorder, true).BorderForeground(theme.Border).Padding(0, 1)
+	activeTabStyle    = inactiveTabStyle.Copy().Border(activeTabBorder, true)
+	hintStyle         = lipgloss.NewStyle().Faint(true)
+	keysStyle         = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#B2B2B2", Dark: "#5C5C5C"})
+	pausedBarColor    = "#9E9E9E"
+	toastStyle        = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#1A1A1A")).Background(theme.Special).Padding(0, 1)
+	windowStyle       = lipgloss.NewStyle().BorderForeground(theme.Border).Padding(2, 0).Align(lipgloss.Center).Border(lipgloss.NormalBorder()).UnsetBorderTop()
+)
+
+// timer is the state of one tab's session.
+type timer struct {
+	Status      ProgressStatus
+	CurrentTime time.Duration
+	Percent     float64
+	StartedAt   time.Time
+	Pauses      []pauseInterval
+	// PreBreakSent is set once the pre-break reminder went out.
+	PreBreakSent bool
+	// Done is set once the timer filled up and its completion is pending.
+	Done bool
+	// TickID is the tick loop driving the timer and LastTick the time its
+	// last tick arrived.
+	TickID   int
+	LastTick time.Time
+	// Extra is the time added to this session with + and -, on top of its
+	// tab's duration.
+	Extra time.Duration
+}
+
+// pausedFor adds up the time the timer spent paused.
+func (t timer) pausedFor() time.Duration {
+	var paused time.Duration
+	for _, p := range t.Pauses {
+		resumed := p.ResumedAt
+		if resumed.IsZero() {
+			resumed = time.Now()
+		}
+		paused += resumed.Sub(p.PausedAt)
+	}
+	return paused
+}
+
+// confirmation is an action waiting for the user to confirm it with y.
+type confirmation struct {
+	Prompt string
+	Action func(m *Model) tea.Cmd
+}
+
+// Model is the state of the app, a tea.Model run by a tea.Program or by
+// RunDaemon.
+type Model struct {
+	Tabs                []Session
+	ActiveTab           int
+	ProgressMode        int
+	ProgressBars        []progress.Model // one per tab
+	Timers              []timer          // one per tab
+	MultiTimer          bool
+	CompletedPomodoros  int
+	FocusTime           time.Duration
+	LongBreakInterval   int
+	NextMode            int
+	FreeForm            bool
+	Width               int
+	Height              int
+	FixedWidth          int // forced layout width, or 0
+	Plain               bool
+	PauseOnBlur         bool
+	Hooks               Hooks
+	Vertical            bool
+	KeysFooter          bool
+	AskNote             bool
+	ProfileStats        bool
+	LongBreakETA        bool
+	DoneTitle           string
+	DoneMessage         string
+	QuietHours          QuietHours
+	TodoFile            string
+	TodoAction          TodoAction
+	Task                string         // todo.txt line of the task being worked on
+	TaskCounts          map[string]int // completed pomodoros by task label
+	Picking             bool           // the task list is open
+	Tasks               []string       // pending tasks shown while Picking
+	Pick                int            // highlighted task while Picking
+	Noting              bool           // the note prompt for LastRecord is open
+	Note                textinput.Model
+	Jotting             bool // the distraction input is open
+	Distraction         textinput.Model
+	BlurPaused          bool // the running session was paused by a blur
+	WatchProcess        string
+	ProcessPaused       bool // the running pomodoro was paused by WatchProcess exiting
+	VerboseLog          bool
+	TickID              int // last tick loop ID handed out
+	SleepPolicy         SleepPolicy
+	Notice              string
+	Confirm             *confirmation
+	ConfirmOverwrite    bool
+	ConfirmQuit         bool
+	PreBreakLead        time.Duration
+	Bell                bool
+	Notifications       bool // desktop notifications are on, toggled with m
+	Sounds              bool // the bell and tones are on, toggled with M
+	LockTabs            bool
+	Strict              bool
+	Notifier            Notifier
+	Icon                string
+	WorkIcon            string
+	BreakIcon           string
+	Stacked             bool
+	IdleQuit            time.Duration
+	MaxSession          time.Duration
+	ShowPercent         bool
+	LastActivity        time.Time
+	TodayPomodoros      int
+	TodayFocus          time.Duration
+	Today               time.Time // day the Today counters belong to
+	DailySummary        bool
+	ScorePomodoroWeight float64
+	ScoreMinuteWeight   float64
+	SetTitle            bool
+	Title               string // last terminal title sent
+	FineCountdown       bool
+	Profile             string
+	BigClock            bool
+	Transcript          bool
+	Events              bool // write streamEvents to stdout, in the daemon
+	EventTicks          bool // include a tick event every tick
+	OtherTab            OtherTabAction
+	ConfirmSkip         SkipConfirm
+	ShortBreakRatio     float64 // work durations per short break, 0 keeps its own
+	LongBreakRatio      float64 // work durations per long break, 0 keeps its own
+	Locked              bool
+	ShowLifetime        bool
+	Lifetime            int // pomodoros ever completed
+	CleanOnly           bool
+	MaxPause            time.Duration
+	Goal                int  // today's goal in pomodoros, 0 if none
+	DefaultGoal         int  // goal a new day starts with
+	GoalDaysMet         int  // of the last GoalDays days with a goal
+	Stats               bool // the stats tab is shown, past the last of Tabs
+	DayStats            dayStats
+	GoalDays            int
+	GoalBar             progress.Model
+	EditingGoal         bool
+	GoalInput           string
+	Repaint             time.Duration
+	Rounding            Rounding
+	StartMode           int  // session started on launch, -1 for none
+	OneShot             bool // quit once the StartMode session completes
+	Completed           bool // the one-shot session completed
+	AskReason           bool // ask why a running session is reset
+	AskingReason        bool
+	Reason              textinput.Model
+	Tones               bool
+	Bare                bool
+	Cooldown            time.Duration
+	StartDelay          time.Duration
+	StartingUntil       time.Time // the pomodoro StartingMode starts then
+	StartID             int       // last start countdown handed out
+	StartingMode        int
+	LastRecord          *sessionRecord // last completion of this run, until undone
+	LastMode            int
+	LastCycle           int // CompletedPomodoros before LastRecord completed
+	Completions         int // sessions completed in this run
+	CooldownUntil       time.Time
+	AutoAdvance         bool // start the next session of the cycle on completion
+	AdvancePending      bool // start it once the cooldown is over
+	GoalStop            bool // no AutoAdvance once the goal is met
+	MinBreak            float64
+	Clock               ClockEmphasis
+	DoneHold            time.Duration
+	Activities          []string
+	Activity            string    // suggested for the last break started
+	RestUntil           time.Time // no pomodoro may start before, by MinBreak
+	Toast               string
+	ToastID             int
+	LastAlert           *alert // last completion notification, for N to send again
+	SnoozeFor           time.Duration
+	MaxSnoozes          int
+	Snoozable           bool // the last completion can be snoozed
+	Snoozes             int  // times the last completion was snoozed
+	SnoozeID            int
+}
+
+// tickMsg advances the timer in Mode when ID matches the timer's TickID.
+// Every timer runs its own tick loop and each new loop gets a fresh ID, so
+// ticks still in flight from a stopped or earlier loop are dropped instead of
+// doubling the timer's speed.
+type tickMsg struct {
+	Mode int
+	ID   int
+	At   time.Time
+}
+
+// sleepThreshold is how late a tick may arrive before the gap is put down to
+// the system having been suspended rather than a busy scheduler.
+const sleepThreshold = 5 * time.Second
+
+// With FineCountdown, the last fineCountdownFrom of a session tick every
+// fineTickInterval so the remaining time shows tenths of a second.
+const (
+	fineCountdownFrom = 10 * time.Second
+	fineTickInterval  = 100 * time.Millisecond
+)
+
+type progressDoneMsg struct {
+	Mode int
+}
+type idleCheckMsg struct{}
+type snoozeMsg struct {
+	ID int
+}
+type repaintMsg struct{}
+type cooldownMsg struct{}
+type startCountdownMsg struct {
+	ID int
+}
+type startMsg struct {
+	Mode int
+}
+type clearToastMsg struct {
+	ID int
+}
+type dayCheckMsg struct {
+	At time.Time
+}
+
+// logFailedMsg reports that a session could not be logged.
+type logFailedMsg struct {
+	Err error
+}
+
+// undoneMsg reports that Record, completed in Mode with Cycle pomodoros in
+// the cycle before it, was cut off the history log, or the error that kept
+// it there.
+type undoneMsg struct {
+	Record      sessionRecord
+	Mode, Cycle int
+	Err         error
+}
+
+// notifyFailedMsg reports that the completion notification could not be
+// sent.
+type notifyFailedMsg struct {
+	Err error
+}
+
+// NewModel returns the Model for cfg, sending its notifications with
+// notifier, with today's totals read from the history log.
+func NewModel(cfg Config, notifier Notifier) Model {
+	m := Model{
+		Tabs:                append([]Session(nil), cfg.Sessions...),
+		ActiveTab:           0, // Tabs index
+		ProgressMode:        0, // Tabs index
+		Timers:              make([]timer, len(cfg.Sessions)),
+		MultiTimer:          cfg.MultiTimer,
+		CompletedPomodoros:  0,
+		FocusTime:           0,
+		LongBreakInterval:   max(cfg.LongBreakInterval, 1),
+		FreeForm:            cfg.FreeForm,
+		VerboseLog:          cfg.VerboseLog,
+		Bell:                cfg.Bell,
+		Notifications:       true,
+		Sounds:              true,
+		LockTabs:            cfg.LockTabs,
+		Strict:              cfg.Strict,
+		Notifier:            notifier,
+		Icon:                notificationIcon(),
+		WorkIcon:            cfg.WorkIcon,
+		BreakIcon:           cfg.BreakIcon,
+		IdleQuit:            cfg.IdleQuit,
+		MaxSession:          cfg.MaxSession,
+		ShowPercent:         cfg.ShowPercent,
+		WatchProcess:        cfg.WatchProcess,
+		SnoozeFor:           cfg.SnoozeFor,
+		MinBreak:            cfg.MinBreak,
+		Activities:          cfg.Activities,
+		Clock:               cfg.Clock,
+		DoneHold:            cfg.DoneHold,
+		AutoAdvance:         cfg.AutoAdvance,
+		MaxSnoozes:          cfg.MaxSnoozes,
+		LastActivity:        time.Now(),
+		ScorePomodoroWeight: cfg.ScorePomodoroWeight,
+		ScoreMinuteWeight:   cfg.ScoreMinuteWeight,
+		SleepPolicy:         cfg.SleepPolicy,
+		PreBreakLead:        cfg.PreBreakLead,
+		ConfirmOverwrite:    cfg.ConfirmOverwrite,
+		ConfirmQuit:         cfg.ConfirmQuit,
+		SetTitle:            cfg.SetTitle,
+		Today:               time.Now(),
+		DailySummary:        cfg.DailySummary,
+		FineCountdown:       cfg.FineCountdown,
+		Profile:             cfg.Profile,
+		BigClock:            cfg.BigClock,
+		Transcript:          cfg.Transcript,
+		Events:              cfg.Events,
+		EventTicks:          cfg.EventTicks,
+		OtherTab:            cfg.OtherTab,
+		ConfirmSkip:         cfg.ConfirmSkip,
+		ShowLifetime:        cfg.ShowLifetime,
+		CleanOnly:           cfg.CleanOnly,
+		MaxPause:            cfg.MaxPause,
+		Goal:                cfg.Goal,
+		DefaultGoal:         cfg.Goal,
+		GoalStop:            cfg.GoalStop,
+		Repaint:             cfg.Repaint,
+		Rounding:            cfg.Rounding,
+		AskReason:           cfg.AskReason,
+		Tones:               cfg.Tones,
+		Bare:                cfg.Bare,
+		Cooldown:            cfg.Cooldown,
+		StartDelay:          cfg.StartDelay,
+		FixedWidth:          cfg.Width,
+		Plain:               cfg.Plain,
+		PauseOnBlur:         cfg.PauseOnBlur,
+		Hooks:               cfg.Hooks,
+		Vertical:            cfg.Vertical,
+		KeysFooter:          cfg.KeysFooter,
+		AskNote:             cfg.AskNote,
+		ProfileStats:        cfg.ProfileStats,
+		LongBreakETA:        cfg.LongBreakETA,
+		DoneTitle:           cfg.DoneTitle,
+		DoneMessage:         cfg.DoneMessage,
+		QuietHours:          cfg.QuietHours,
+		TodoFile:            cfg.TodoFile,
+		TodoAction:          cfg.TodoAction,
+		TaskCounts:          map[string]int{},
+		Note:                textinput.New(),
+		Reason:              textinput.New(),
+		Distraction:         textinput.New(),
+		StartMode:           -1,
+		GoalBar:             progress.New(progress.WithSolidFill(theme.Special.Dark), progress.WithoutPercentage(), progress.WithWidth(20)),
+	}
+	if cfg.RatioBreaks {
+		m.ShortBreakRatio, m.LongBreakRatio = cfg.ShortBreakRatio, cfg.LongBreakRatio
+	}
+	for range m.Tabs {
+		bar := progress.New(progress.WithGradient(theme.Highlight, theme.HighlightEnd), progress.WithoutPercentage())
+		if cfg.BarFull != 0 {
+			bar.Full = rune(cfg.BarFull)
+		}
+		if cfg.BarEmpty != 0 {
+			bar.Empty = rune(cfg.BarEmpty)
+		}
+		if cfg.Width > 0 {
+			bar.Width = barWidth(cfg.Width, cfg.ShowPercent)
+		}
+		m.ProgressBars = append(m.ProgressBars, bar)
+	}
+	if cfg.BarFull != 0 {
+		m.GoalBar.Full = rune(cfg.BarFull)
+	}
+	if cfg.BarEmpty != 0 {
+		m.GoalBar.Empty = rune(cfg.BarEmpty)
+	}
+	m.Reason.Placeholder = tr("interrupted by...")
+	m.Reason.CharLimit = 80
+	m.Reason.Cursor.SetMode(cursor.CursorStatic)
+	m.Distraction.Placeholder = tr("what came up?")
+	m.Distraction.CharLimit = 200
+	m.Distraction.Cursor.SetMode(cursor.CursorStatic)
+	m.Note.Placeholder = tr("what did you get done?")
+	m.Note.CharLimit = 200
+	m.Note.Cursor.SetMode(cursor.CursorStatic)
+	if goal, ok := dayGoal(time.Now()); ok {
+		m.Goal = goal
+	}
+	for i := range m.Timers {
+		m.Timers[i].Status = Idle
+	}
+	m.NextMode = m.workIndex()
+	if cfg.FocusWork {
+		m.ActiveTab = m.workIndex()
+	}
+	m.scaleBreaks()
+	if cfg.Start != "" {
+		m.StartMode = m.indexOfKind(cfg.Start)
+		m.OneShot = true
+	} else if cfg.AutoStart {
+		m.StartMode = m.workIndex()
+	}
+	m.loadTotals()
+	if at, ok := damagedHistory(); HistoryBackups && ok {
+		m.Confirm = &confirmation{
+			Prompt: trf("The history log is damaged - restore the backup from %s? (y/n)", at.Format("Jan 2 15:04")),
+			Action: func(m *Model) tea.Cmd {
+				if err := restoreHistory(); err != nil {
+					m.Notice = trf("Restore failed: %v", err)
+					return nil
+				}
+				m.loadTotals()
+				return nil
+			},
+		}
+	}
+	return m
+}
+
+// loadTotals counts today's and, if shown, the lifetime pomodoros from the
+// history log.
+func (m *Model) loadTotals() {
+	if records, err := readHistory(); err == nil {
+		if m.ProfileStats {
+			records = filterProfile(records, m.Profile)
+		}
+		if m.CleanOnly {
+			records = filterClean(records)
+		}
+		m.TodayPomodoros, m.TodayFocus = todayTotals(records, time.Now())
+		m.TaskCounts = taskCounts(records)
+		if goals, err := readGoals(); err == nil {
+			m.GoalDaysMet, m.GoalDays = goalDaysMet(records, goals, m.DefaultGoal, time.Now())
+		}
+	}
+	if m.ShowLifetime {
+		m.Lifetime, _ = lifetimePomodoros()
+	}
+}
+
+func (m *Model) resetTimer(mode int) {
+	m.Timers[mode] = timer{Status: Idle}
+	m.LastActivity = time.Now()
+}
+
+func (m *Model) resetProgress() {
+	m.resetTimer(m.ProgressMode)
+}
+
+// focusedTimer is the timer r and ctrl+r act on: the current session's, or
+// with MultiTimer the viewed tab's, leaving the other tabs' timers running.
+func (m Model) focusedTimer() int {
+	if m.MultiTimer {
+		return m.ActiveTab
+	}
+	return m.ProgressMode
+}
+
+// held reports whether Strict keeps the session in mode from being paused,
+// reset, restarted or skipped: a pomodoro that is on. Breaks are never held.
+func (m Model) held(mode int) bool {
+	return m.Strict && m.Tabs[mode].Kind == Work && m.Timers[mode].Status != Idle
+}
+
+// idle reports whether no timer is running or paused.
+func (m Model) idle() bool {
+	for _, t := range m.Timers {
+		if t.Status != Idle {
+			return false
+		}
+	}
+	return true
+}
+
+// startTimer starts the session in mode from zero and makes it the current
+// one.
+func (m *Model) startTimer(mode int) tea.Cmd {
+	if m.disabled(mode) {
+		m.Notice = trf("%s is turned off", tr(m.Tabs[mode].Name))
+		return nil
+	}
+	if left := m.restLeft(mode); left > 0 {
+		m.Notice = trf("Rest a bit longer (%s left)", left)
+		return nil
+	}
+	m.ProgressMode = mode
+	m.Timers[mode] = timer{Status: Running, StartedAt: time.Now()}
+	m.Snoozable = false
+	if m.Tabs[mode].Kind != Work && len(m.Activities) > 0 {
+		m.Activity = m.Activities[rand.Intn(len(m.Activities))]
+	}
+	ticking := m.startTicking(mode)
+	return tea.Batch(m.tone(startTone), ticking)
+}
+
+// tone plays tone if Tones are enabled, outside QuietHours.
+func (m Model) tone(tone Tone) tea.Cmd {
+	if !m.Tones || !m.Sounds || m.QuietHours.contains(time.Now()) {
+		return nil
+	}
+
+	notifier := m.Notifier
+	return func() tea.Msg {
+		notifier.Tone(tone)
+		return nil
+	}
+}
+
+func (m *Model) pauseTimer(mode int) {
+	t := &m.Timers[mode]
+	t.Status = Paused
+	t.Pauses = append(t.Pauses, pauseInterval{PausedAt: time.Now()})
+}
+
+func (m *Model) resumeTimer(mode int) tea.Cmd {
+	t := &m.Timers[mode]
+	t.Status = Running
+	t.Pauses[len(t.Pauses)-1].ResumedAt = time.Now()
+	return m.startTicking(mode)
+}
+
+// handleCompletion records a finished session of the given mode and sets
+// NextMode to the session that should follow it in the pomodoro cycle. The
+// returned command appends the session to the history log, unless a pomodoro
+// waits for its note first.
+func (m *Model) handleCompletion(mode int) tea.Cmd {
+	// A note still being written belongs to the session before this one.
+	var pending tea.Cmd
+	if m.Noting {
+		pending = m.saveNote(m.Note.Value())
+	}
+
+	t := m.Timers[mode]
+	record := sessionRecord{
+		Type:            m.Tabs[mode].Kind,
+		Name:            m.Tabs[mode].Name,
+		DurationSeconds: int64(m.getDurationByIndex(mode).Seconds()),
+		StartedAt:       t.StartedAt,
+		CompletedAt:     time.Now(),
+		Profile:         m.Profile,
+	}
+	// The session ended at the tick that filled it up, not when the
+	// completion went through a moment later.
+	end := t.LastTick
+	if end.IsZero() {
+		end = record.CompletedAt
+	}
+	record.ElapsedSeconds = int64(max(end.Sub(t.StartedAt)-t.pausedFor(), 0).Round(time.Second).Seconds())
+	if m.VerboseLog {
+		record.Pauses = m.Timers[mode].Pauses
+	}
+	if m.CleanOnly && m.Timers[mode].pausedFor() > m.MaxPause {
+		record.NotClean = true
+	}
+	var todo tea.Cmd
+	if m.Task != "" && record.Type == Work {
+		record.Task = todoLabel(m.Task)
+		m.TaskCounts[record.Task]++
+		todo = m.updateTask()
+	}
+	if todo != nil {
+		pending = tea.Batch(pending, todo)
+	}
+
+	if m.Tabs[mode].Kind == Work {
+		m.Lifetime++
+		if !record.NotClean {
+			m.FocusTime += m.getDurationByIndex(mode)
+			m.TodayPomodoros++
+			m.TodayFocus += m.getDurationByIndex(mode)
+		}
+	}
+
+	m.LastCycle = m.CompletedPomodoros
+	m.advanceCycle(mode)
+	m.resetTimer(mode)
+	m.LastRecord, m.LastMode = &record, mode
+	m.Completions++
+	if m.MinBreak > 0 && record.Type == Work {
+		rest := time.Duration(min(m.MinBreak, 1) * float64(m.getDurationByIndex(m.NextMode)))
+		m.RestUntil = record.CompletedAt.Add(rest)
+	}
+
+	if m.AskNote && record.Type == Work && !m.OneShot {
+		// The record is logged once the note is in.
+		m.Noting = true
+		m.Note.SetValue("")
+		return tea.Batch(pending, m.Note.Focus())
+	}
+	log := logRecord(record)
+	if pending != nil {
+		// Only batch when there is something to batch, as a one-shot
+		// session sequences this before quitting.
+		return tea.Batch(pending, log)
+	}
+	return log
+}
+
+// logRecord returns the command logging record, which reports a failure in
+// a logFailedMsg.
+func logRecord(record sessionRecord) tea.Cmd {
+	return func() tea.Msg {
+		if err := sessionLog.Log(record); err != nil {
+			return logFailedMsg{err}
+		}
+		return nil
+	}
+}
+
+// updateTask applies TodoAction to the task for a completed pomodoro, in
+// the model right away and in the todo.txt file by the returned command.
+func (m *Model) updateTask() tea.Cmd {
+	task := m.Task
+	switch m.TodoAction {
+	case TodoCount:
+		m.Task = countPomodoro(task)
+	case TodoComplete:
+		m.Task = ""
+	default:
+		return nil
+	}
+
+	updated, path := m.Task, m.TodoFile
+	if updated == "" {
+		updated = completeTask(task, time.Now())
+	}
+	return func() tea.Msg {
+		updateTodo(path, task, updated)
+		return nil
+	}
+}
+
+// saveNote logs the completed session waiting on its note, with note.
+func (m *Model) saveNote(note string) tea.Cmd {
+	m.Noting = false
+	m.Note.Blur()
+	m.LastRecord.Note = strings.TrimSpace(note)
+	record := *m.LastRecord
+	return logRecord(record)
+}
+
+// undoCompletion takes back the last session completed in this run by
+// cutting its record off the history log. Its counts and its place in the
+// cycle follow once the undoneMsg says the record is gone.
+func (m *Model) undoCompletion() tea.Cmd {
+	msg := undoneMsg{Record: *m.LastRecord, Mode: m.LastMode, Cycle: m.LastCycle}
+	m.LastRecord = nil
+	return func() tea.Msg {
+		msg.Err = removeLastRecord(msg.Record)
+		return msg
+	}
+}
+
+// rollBack takes the counts and the place in the cycle of the completion
+// undone by msg back.
+func (m *Model) rollBack(msg undoneMsg) {
+	record := msg.Record
+	m.Snoozable = false
+	m.CompletedPomodoros = msg.Cycle
+	if record.Type == Work {
+		m.Lifetime--
+		if !record.NotClean {
+			duration := time.Duration(record.DurationSeconds) * time.Second
+			m.FocusTime -= duration
+			m.TodayPomodoros--
+			m.TodayFocus -= duration
+		}
+		if record.Task != "" {
+			m.TaskCounts[record.Task]--
+		}
+	}
+	m.NextMode = msg.Mode
+	m.RestUntil = time.Time{}
+}
+
+// abandon returns the command logging the session in mode as reset before
+// it completed, for the given reason. The log no longer ends in LastRecord,
+// so it can't be undone after.
+func (m *Model) abandon(mode int, reason string) tea.Cmd {
+	m.LastRecord = nil
+	record := sessionRecord{
+		Type:            m.Tabs[mode].Kind,
+		Name:            m.Tabs[mode].Name,
+		DurationSeconds: int64(m.Timers[mode].CurrentTime.Seconds()),
+		StartedAt:       m.Timers[mode].StartedAt,
+		CompletedAt:     time.Now(),
+		Abandoned:       true,
+		Reason:          reason,
+		Profile:         m.Profile,
+	}
+	return logRecord(record)
+}
+
+// skip ends the session in mode without completing it, logging it as
+// skipped, and moves on to the session after it: the next break for a
+// pomodoro, which does not count towards the long break, or the next
+// pomodoro for a break. With AutoAdvance that session starts right away.
+func (m *Model) skip(mode int) tea.Cmd {
+	t := m.Timers[mode]
+	record := sessionRecord{
+		Type:            m.Tabs[mode].Kind,
+		Name:            m.Tabs[mode].Name,
+		DurationSeconds: int64(t.CurrentTime.Seconds()),
+		StartedAt:       t.StartedAt,
+		CompletedAt:     time.Now(),
+		Abandoned:       true,
+		Skipped:         true,
+		Profile:         m.Profile,
+	}
+	log := logRecord(record)
+	m.LastRecord = nil
+
+	m.resetTimer(mode)
+	m.Notice = trf("%s skipped", tr(m.Tabs[mode].Name))
+	if m.OneShot && mode == m.StartMode {
+		return tea.Sequence(log, m.quit())
+	}
+	switch m.Tabs[mode].Kind {
+	case Work:
+		m.NextMode = m.breakIndex()
+		if short := m.indexOfKind(ShortBreak); short >= 0 {
+			m.NextMode = short
+		}
+	default:
+		// Cutting a break short is the way to get back to work early.
+		m.NextMode = m.workIndex()
+		m.RestUntil = time.Time{}
+	}
+	return tea.Batch(log, m.autoAdvance())
+}
+
+// stopRunaway resets the session in mode for having run for MaxSession
+// without completing, logging it as abandoned.
+func (m Model) stopRunaway(mode int) (tea.Model, tea.Cmd) {
+	cmd := m.abandon(mode, "ran past -max-session")
+	m.resetTimer(mode)
+	m.Notice = trf("%s stopped after running for %s", tr(m.Tabs[mode].Name), m.MaxSession)
+	if m.OneShot && mode == m.StartMode {
+		return m, tea.Sequence(cmd, m.quit())
+	}
+	return m, cmd
+}
+
+// advanceCycle moves the pomodoro cycle past a finished session of the given
+// mode, counting it if it was a pomodoro, and points NextMode at what follows.
+// A long break ends the cycle and starts the count over.
+func (m *Model) advanceCycle(mode int) {
+	switch m.Tabs[mode].Kind {
+	case Work:
+		m.CompletedPomodoros++
+		m.NextMode = m.breakIndex()
+	case LongBreak:
+		m.CompletedPomodoros = 0
+		m.NextMode = m.workIndex()
+	default:
+		m.NextMode = m.workIndex()
+	}
+}
+
+// scaleBreaks sets the break durations to a fraction of the work session's
+// by ShortBreakRatio and LongBreakRatio. It has to run again whenever the
+// work duration changes.
+func (m *Model) scaleBreaks() {
+	work := m.getDurationByIndex(m.workIndex())
+	for i := range m.Tabs {
+		ratio := m.ShortBreakRatio
+		switch m.Tabs[i].Kind {
+		case Work:
+			continue
+		case LongBreak:
+			ratio = m.LongBreakRatio
+		}
+		if ratio > 0 {
+			m.Tabs[i].Duration = time.Duration(float64(work) / ratio).Round(time.Second)
+		}
+	}
+}
+
+// focusScore rates today's work as ScorePomodoroWeight points per completed
+// pomodoro plus ScoreMinuteWeight points per minute of focus.
+func (m Model) focusScore() int {
+	return int(math.Round(float64(m.TodayPomodoros)*m.ScorePomodoroWeight + m.TodayFocus.Minutes()*m.ScoreMinuteWeight))
+}
+
+func (m Model) getDurationByIndex(index int) time.Duration {
+	if index < 0 || index >= len(m.Tabs) {
+		return 0
+	}
+	return m.Tabs[index].Duration + m.Timers[index].Extra
+}
+
+// adjustStep is how much + and - lengthen or shorten a session by.
+const adjustStep = time.Minute
+
+// adjust lengthens the session in mode by d, or shortens it for a negative
+// d, leaving the tab's duration for the sessions after it. Shortened to no
+// more than it has run, the session completes now.
+func (m *Model) adjust(mode int, d time.Duration) tea.Cmd {
+	t := &m.Timers[mode]
+	duration := max(m.getDurationByIndex(mode)+d, t.CurrentTime)
+	t.Extra = duration - m.Tabs[mode].Duration
+	if duration <= t.CurrentTime {
+		t.Percent, t.Done = 1, true
+		return progressDone(mode, m.DoneHold)
+	}
+
+	t.Percent = min(t.CurrentTime.Seconds()/duration.Seconds(), 1)
+	m.Notice = trf("%s is now %s long", tr(m.Tabs[mode].Name), formatDuration(duration))
+	return nil
+}
+
+// indexOfKind returns the first tab of the given kind, or -1 if there is none.
+// Disabled tabs are passed over, so the cycle skips them.
+func (m Model) indexOfKind(kind SessionKind) int {
+	for i, t := range m.Tabs {
+		if t.Kind == kind && !m.disabled(i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// disabled reports whether the tab in mode has no duration to run, which is
+// how a session is turned off.
+func (m Model) disabled(mode int) bool {
+	return m.getDurationByIndex(mode) <= 0
+}
+
+func (m Model) workIndex() int {
+	return max(m.indexOfKind(Work), 0)
+}
+
+// breakIndex picks the break that follows a just completed pomodoro: the long
+// break every LongBreakInterval pomodoros, the short break otherwise. Missing
+// break tabs fall back to the other break, then to the work tab.
+func (m Model) breakIndex() int {
+	short, long := m.indexOfKind(ShortBreak), m.indexOfKind(LongBreak)
+	if long >= 0 && (short < 0 || m.CompletedPomodoros%m.LongBreakInterval == 0) {
+		return long
+	}
+	if short >= 0 {
+		return short
+	}
+	return m.workIndex()
+}
+
+// untilLongBreak adds up the time left before the next long break: the rest
+// of the current session and the sessions of the cycle up to the break. It
+// reports false during a long break, or if the cycle has none.
+func (m Model) untilLongBreak() (time.Duration, bool) {
+	var total time.Duration
+	if current := m.ProgressMode; m.Timers[current].Status != Idle {
+		if m.Tabs[current].Kind == LongBreak {
+			return 0, false
+		}
+		_, remaining := m.tabProgress(current)
+		total += remaining
+		m.advanceCycle(current)
+	}
+
+	for i := 0; i <= 2*m.LongBreakInterval; i++ {
+		mode := m.NextMode
+		if m.Tabs[mode].Kind == LongBreak {
+			return total, true
+		}
+		total += m.getDurationByIndex(mode)
+		m.advanceCycle(mode)
+	}
+	return 0, false
+}
+
+func ordinal(n int) string {
+	if language != "en" {
+		return fmt.Sprintf("%d.", n)
+	}
+
+	suffix := "th"
+	switch n % 10 {
+	case 1:
+		suffix = "st"
+	case 2:
+		suffix = "nd"
+	case 3:
+		suffix = "rd"
+	}
+	if n%100 >= 11 && n%100 <= 13 {
+		suffix = "th"
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
+
+// nextHint describes the session NextMode points at, e.g.
+// "Next: Pomodoro (3rd of 4)".
+func (m Model) nextHint() string {
+	next := m.Tabs[m.NextMode]
+	if next.Kind != Work {
+		return trf("Next: %s", tr(next.Name))
+	}
+
+	position := m.CompletedPomodoros%m.LongBreakInterval + 1
+	return trf("Next: %s (%s of %d)", tr(next.Name), ordinal(position), m.LongBreakInterval)
+}
+
+func tick(mode, id int, interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
+		return tickMsg{Mode: mode, ID: id, At: t}
+	})
+}
+
+// tickInterval is a second, or fineTickInterval once FineCountdown is on and
+// the timer in mode is within fineCountdownFrom of its end.
+func (m Model) tickInterval(mode int) time.Duration {
+	if m.FineCountdown && m.getDurationByIndex(mode)-m.Timers[mode].CurrentTime <= fineCountdownFrom {
+		return fineTickInterval
+	}
+	return time.Second
+}
+
+// notificationIcon prefers an icon installed under the XDG data dirs and
+// falls back to the one built into the binary. Without either the
+// notifications go out without an icon.
+func notificationIcon() string {
+	if path := findDataFile("pomodoro.png"); path != "" {
+		return path
+	}
+	path, err := embeddedIcon()
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
+// completionIcon returns the configured icon for the kind of session that
+// completed, or the default icon if none is set or it cannot be found.
+func (m Model) completionIcon(mode int) string {
+	icon := m.BreakIcon
+	if m.Tabs[mode].Kind == Work {
+		icon = m.WorkIcon
+	}
+
+	if icon != "" {
+		if _, err := os.Stat(icon); err == nil {
+			return icon
+		}
+	}
+	return m.Icon
+}
+
+// completionData is what DoneTitle and DoneMessage templates can refer to,
+// e.g. "{{.Completed}} pomodoros today".
+type completionData struct {
+	// Completed counts today's pomodoros, this one included.
+	Completed int
+	Goal      int
+	Type      SessionKind
+	Name      string
+	Duration  time.Duration
+}
+
+// expand executes text as a template over data. A template that does not
+// parse or execute is used as it is.
+func expand(text string, data completionData) string {
+	tmpl, err := template.New("").Parse(text)
+	if err != nil {
+		return text
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return text
+	}
+	return b.String()
+}
+
+// alert is a completion notification, kept so it can be sent again.
+type alert struct {
+	Title, Message, Icon string
+}
+
+// completionAlert returns the notification for the session in mode having
+// finished.
+func (m Model) completionAlert(mode int) alert {
+	data := completionData{
+		Completed: m.TodayPomodoros,
+		Goal:      m.Goal,
+		Type:      m.Tabs[mode].Kind,
+		Name:      m.Tabs[mode].Name,
+		Duration:  m.getDurationByIndex(mode),
+	}
+	if data.Type == Work {
+		data.Completed++
+	}
+	a := alert{Title: tr("Pomodoro done"), Icon: m.completionIcon(mode)}
+	if m.DoneTitle != "" {
+		a.Title = expand(m.DoneTitle, data)
+	}
+	if m.DoneMessage != "" {
+		a.Message = expand(m.DoneMessage, data)
+	}
+	return a
+}
+
+// notifyCompletion sends a, unless it is QuietHours.
+func (m Model) notifyCompletion(a alert) tea.Cmd {
+	if m.QuietHours.contains(time.Now()) {
+		return nil
+	}
+	return m.sendAlert(a)
+}
+
+// sendAlert alerts the user with a, ringing the bell along with it if Bell
+// is set. Muting either leaves the other.
+func (m Model) sendAlert(a alert) tea.Cmd {
+	notifier, notify, bell, sound := m.Notifier, m.Notifications, m.Bell, m.Sounds
+	return func() tea.Msg {
+		var err error
+		if notify {
+			err = notifier.Alert(a.Title, a.Message, a.Icon)
+		}
+		if sound && (bell || err != nil) {
+			// Without the notification the bell is all that is left to
+			// get the user's attention.
+			fmt.Fprint(os.Stdout, "\a")
+		}
+		if err != nil {
+			return notifyFailedMsg{err}
+		}
+		return nil
+	}
+}
+
+// preBreakReminder returns a heads-up notification once the pomodoro in mode
+// is within PreBreakLead of its end, or nil if it is not due.
+func (m *Model) preBreakReminder(mode int) tea.Cmd {
+	t := &m.Timers[mode]
+	remaining := m.getDurationByIndex(mode) - t.CurrentTime
+	if m.PreBreakLead <= 0 || !m.Notifications || t.PreBreakSent || m.Tabs[mode].Kind != Work || remaining > m.PreBreakLead || remaining <= 0 || m.QuietHours.contains(time.Now()) {
+		return nil
+	}
+
+	t.PreBreakSent = true
+	notifier, message, icon := m.Notifier, trf("Wrap up - break in %s", remaining), m.Icon
+	return func() tea.Msg {
+		notifier.Notify(tr("Pomodoro"), message, icon)
+		return nil
+	}
+}
+
+// progressDone completes the session in mode after hold, for which its full
+// bar stays on screen.
+func progressDone(mode int, hold time.Duration) tea.Cmd {
+	return tea.Tick(hold, func(time.Time) tea.Msg {
+		return progressDoneMsg{Mode: mode}
+	})
+}
+
+// repaint schedules the next redraw of the view, which otherwise only
+// happens on ticks and key presses and so would go stale while idle.
+func repaint(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return repaintMsg{}
+	})
+}
+
+// cooldown returns how long until a new session may start after the last
+// one completed, or 0 if it may start now.
+func (m Model) cooldown() time.Duration {
+	return max(time.Until(m.CooldownUntil).Round(time.Second), 0)
+}
+
+// restLeft returns how much longer the break after the last pomodoro has
+// to go on for, by MinBreak, before a session in mode may start. Only work
+// sessions wait.
+func (m Model) restLeft(mode int) time.Duration {
+	if m.Tabs[mode].Kind != Work {
+		return 0
+	}
+	return max(time.Until(m.RestUntil).Round(time.Second), 0)
+}
+
+// autoAdvance starts the next session of the cycle once one completed, if
+// AutoAdvance is on, or once the cooldown is over. With GoalStop it stops
+// once today's goal is met.
+func (m *Model) autoAdvance() tea.Cmd {
+	if !m.AutoAdvance || m.FreeForm || m.MultiTimer {
+		return nil
+	}
+	if m.GoalStop && m.Goal > 0 && m.TodayPomodoros >= m.Goal {
+		return nil
+	}
+	if m.cooldown() > 0 {
+		m.AdvancePending = true
+		return nil
+	}
+	m.ActiveTab, m.Stats = m.NextMode, false
+	return m.startTimer(m.NextMode)
+}
+
+// getReady starts the session in mode, a pomodoro after counting
+// StartDelay down first.
+func (m *Model) getReady(mode int) tea.Cmd {
+	if m.StartDelay <= 0 || m.Tabs[mode].Kind != Work || m.disabled(mode) || m.restLeft(mode) > 0 {
+		return m.startTimer(mode)
+	}
+
+	m.StartingUntil, m.StartingMode = time.Now().Add(m.StartDelay), mode
+	m.StartID++
+	return startCountdown(m.StartID, m.StartDelay)
+}
+
+// starting reports whether a pomodoro is counting down to its start.
+func (m Model) starting() bool {
+	return !m.StartingUntil.IsZero()
+}
+
+// countdown is the seconds left before the starting pomodoro starts,
+// rounded up so it counts 3, 2, 1.
+func (m Model) countdown() int {
+	return int(math.Ceil(time.Until(m.StartingUntil).Seconds()))
+}
+
+// startCountdown ticks every second of the countdown, or at its end.
+func startCountdown(id int, left time.Duration) tea.Cmd {
+	return tea.Tick(min(left, time.Second), func(time.Time) tea.Msg {
+		return startCountdownMsg{ID: id}
+	})
+}
+
+// startCooldown holds off new sessions for Cooldown, ticking every second
+// to count the wait down on screen.
+func (m *Model) startCooldown() tea.Cmd {
+	if m.Cooldown <= 0 {
+		return nil
+	}
+
+	m.CooldownUntil = time.Now().Add(m.Cooldown)
+	return cooldownTick()
+}
+
+func cooldownTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return cooldownMsg{}
+	})
+}
+
+// toastDuration is how long a toast stays up.
+const toastDuration = 3 * time.Second
+
+// showToast puts up a message in the window and returns the command that
+// takes it down again, unless a newer toast replaced it by then.
+func (m *Model) showToast(message string) tea.Cmd {
+	m.Toast = message
+	m.ToastID++
+	id := m.ToastID
+	return tea.Tick(toastDuration, func(time.Time) tea.Msg {
+		return clearToastMsg{ID: id}
+	})
+}
+
+// idleCheck schedules the next check of how long the app has sat idle.
+func idleCheck(timeout time.Duration) tea.Cmd {
+	return tea.Tick(min(timeout, time.Minute), func(time.Time) tea.Msg {
+		return idleCheckMsg{}
+	})
+}
+
+// dayCheck schedules the next check for the day rolling over, at midnight
+// or DayStartHour. It checks at least every minute, since a timer may sleep
+// past the rollover with the system.
+func dayCheck() tea.Cmd {
+	now := time.Now()
+	y, mo, d := statsDay(now).Date()
+	rollover := time.Date(y, mo, d+1, int(DayStartHour), 0, 0, 0, now.Location())
+	return tea.Tick(min(rollover.Sub(now), time.Minute), func(t time.Time) tea.Msg {
+		return dayCheckMsg{At: t}
+	})
+}
+
+// dailySummary sends a notification with the totals of the day that just
+// ended.
+func (m Model) dailySummary() tea.Cmd {
+	if !m.Notifications {
+		return nil
+	}
+	notifier, message, icon := m.Notifier, trf("Pomodoros: %d, focus: %s", m.TodayPomodoros, m.TodayFocus), m.Icon
+	return func() tea.Msg {
+		notifier.Notify(tr("Daily summary"), message, icon)
+		return nil
+	}
+}
+
+// startTicking begins a fresh tick loop for the timer in mode, orphaning
+// any loop it had before.
+func (m *Model) startTicking(mode int) tea.Cmd {
+	m.TickID++
+	t := &m.Timers[mode]
+	t.TickID = m.TickID
+	t.LastTick = time.Now()
+	return tick(mode, t.TickID, m.tickInterval(mode))
+}
+
+// handleSleep deals with the system having slept through the timer in mode
+// since its last tick: the clock already credits the time slept, unless
+// SleepPolicy pauses the timer from the moment the ticks stopped.
+func (m *Model) handleSleep(mode int) {
+	t := &m.Timers[mode]
+	switch m.SleepPolicy {
+	case SleepPause:
+		t.Status = Paused
+		t.Pauses = append(t.Pauses, pauseInterval{PausedAt: t.LastTick})
+		m.Notice = tr("System slept - session paused")
+	default:
+		m.Notice = tr("System slept - session adjusted")
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	cmds := []tea.Cmd{dayCheck()}
+	if m.StartMode >= 0 {
+		mode := m.StartMode
+		cmds = append(cmds, func() tea.Msg {
+			return startMsg{Mode: mode}
+		})
+	}
+	if m.Repaint > 0 {
+		cmds = append(cmds, repaint(m.Repaint))
+	}
+	if m.IdleQuit > 0 {
+		cmds = append(cmds, idleCheck(m.IdleQuit))
+	}
+	if m.WatchProcess != "" {
+		cmds = append(cmds, processCheck(m.WatchProcess))
+	}
+	return tea.Batch(cmds...)
+}
+
+// windowTitle shows the current session in the terminal title, e.g.
+// "23:41 – Pomodoro", so it stays visible while the window is unfocused.
+func (m Model) windowTitle() string {
+	name := tr(m.Tabs[m.ProgressMode].Name)
+	if m.Timers[m.ProgressMode].Status == Idle {
+		return name
+	}
+
+	_, remaining := m.tabProgress(m.ProgressMode)
+	return formatDuration(remaining) + " – " + name
+}
+
+// runningPomodoro reports whether a pomodoro is running on any tab, which
+// ConfirmQuit asks about before quitting.
+func (m Model) runningPomodoro() bool {
+	for i, t := range m.Timers {
+		if t.Status == Running && m.Tabs[i].Kind == Work {
+			return true
+		}
+	}
+	return false
+}
+
+// quit finishes the transcript and clears the terminal title, if they are
+// enabled, before quitting.
+func (m Model) quit() tea.Cmd {
+	var cmds []tea.Cmd
+	if line := m.finalTranscript(); m.Transcript && line != "" {
+		cmds = append(cmds, tea.Println(line))
+	}
+	if m.SetTitle {
+		cmds = append(cmds, tea.SetWindowTitle(""))
+	}
+	if m.Noting {
+		// Keep the session whose note was still being written.
+		cmds = append(cmds, m.saveNote(m.Note.Value()))
+	}
+	if len(cmds) == 0 {
+		return tea.Quit
+	}
+	return tea.Sequence(append(cmds, tea.Quit)...)
+}
+
+// ExitAbandoned is the exit status of a -start session quit before it
+// completed.
+const ExitAbandoned = 3
@@ -0,0 +1,465 @@
+package pomodoro
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// isolate points the XDG directories and the home directory at a temporary
+// directory and clears the POMODORO_ settings, so a test neither reads nor
+// writes the user's own files or takes up their environment.
+func isolate(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_DATA_HOME", dir+"/data")
+	t.Setenv("XDG_CONFIG_HOME", dir+"/config")
+	t.Setenv("XDG_CACHE_HOME", dir+"/cache")
+	t.Setenv("XDG_DATA_DIRS", dir+"/none")
+	for _, key := range configKeys {
+		t.Setenv("POMODORO_"+strings.ToUpper(key), "")
+	}
+	return dir
+}
+
+// newTestModel returns the model for cfg with nothing of the user's loaded.
+func newTestModel(t *testing.T, cfg Config) Model {
+	t.Helper()
+	isolate(t)
+	return NewModel(cfg, nil)
+}
+
+// finishTimer makes the timer in mode look as if it has just run for its
+// full duration.
+func finishTimer(m *Model, mode int) {
+	d := m.getDurationByIndex(mode)
+	m.ProgressMode = mode
+	m.Timers[mode] = timer{
+		Status:      Running,
+		StartedAt:   time.Now().Add(-d),
+		LastTick:    time.Now(),
+		CurrentTime: d,
+		Done:        true,
+	}
+}
+
+func TestHandleCompletion(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      SessionKind
+		completed int // CompletedPomodoros before the session
+		wantNext  SessionKind
+		wantCycle int
+		wantToday int
+	}{
+		{"pomodoro", Work, 0, ShortBreak, 1, 1},
+		{"fourth pomodoro", Work, 3, LongBreak, 4, 1},
+		{"short break", ShortBreak, 1, Work, 1, 0},
+		{"long break", LongBreak, 4, Work, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t, defaultConfig())
+			m.CompletedPomodoros = tt.completed
+			mode := m.indexOfKind(tt.mode)
+			finishTimer(&m, mode)
+
+			if cmd := m.handleCompletion(mode); cmd == nil {
+				t.Error("handleCompletion returned no command to log the session")
+			}
+			if got := m.Tabs[m.NextMode].Kind; got != tt.wantNext {
+				t.Errorf("next session = %s, want %s", got, tt.wantNext)
+			}
+			if m.CompletedPomodoros != tt.wantCycle {
+				t.Errorf("CompletedPomodoros = %d, want %d", m.CompletedPomodoros, tt.wantCycle)
+			}
+			if m.TodayPomodoros != tt.wantToday {
+				t.Errorf("TodayPomodoros = %d, want %d", m.TodayPomodoros, tt.wantToday)
+			}
+			wantFocus := time.Duration(tt.wantToday) * m.getDurationByIndex(m.workIndex())
+			if m.TodayFocus != wantFocus {
+				t.Errorf("TodayFocus = %s, want %s", m.TodayFocus, wantFocus)
+			}
+			if m.Timers[mode].Status != Idle {
+				t.Errorf("timer status = %s, want %s", m.Timers[mode].Status, Idle)
+			}
+			if m.LastRecord == nil || m.LastRecord.Type != tt.mode {
+				t.Errorf("LastRecord = %+v, want a %s record", m.LastRecord, tt.mode)
+			}
+		})
+	}
+}
+
+func TestExtendedSessionLogsElapsed(t *testing.T) {
+	m := newTestModel(t, defaultConfig())
+	mode := m.workIndex()
+	m, _ = send(m, key(" "))
+	m, _ = send(m, key("+"))
+
+	// The pomodoro ran 28m and a bit, 2m of it paused, by the tick that
+	// filled it.
+	now := time.Now()
+	timer := &m.Timers[mode]
+	timer.StartedAt = now.Add(-(28*time.Minute + 700*time.Millisecond))
+	timer.Pauses = []pauseInterval{{PausedAt: timer.StartedAt.Add(time.Minute), ResumedAt: timer.StartedAt.Add(3 * time.Minute)}}
+	timer.LastTick = now.Add(-time.Second)
+	m, _ = send(m, tickMsg{Mode: mode, ID: timer.TickID, At: now})
+	m, _ = send(m, tickMsg{Mode: mode, ID: m.Timers[mode].TickID, At: now.Add(time.Second)})
+	if !m.Timers[mode].Done {
+		t.Fatal("the extended pomodoro did not complete")
+	}
+	if msg := m.handleCompletion(mode)(); msg != nil {
+		t.Fatalf("logging the session returned %v", msg)
+	}
+
+	records, err := readHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("history has %d records, want 1", len(records))
+	}
+	record := records[0]
+	if want := int64((25*time.Minute + adjustStep).Seconds()); record.DurationSeconds != want {
+		t.Errorf("DurationSeconds = %d, want the extended %d", record.DurationSeconds, want)
+	}
+	if want := int64((26*time.Minute + time.Second).Seconds()); record.ElapsedSeconds != want {
+		t.Errorf("ElapsedSeconds = %d, want the %d it ran for", record.ElapsedSeconds, want)
+	}
+}
+
+func TestWindowTitle(t *testing.T) {
+	tests := []struct {
+		duration, current time.Duration
+		want              string
+	}{
+		{25 * time.Minute, 90 * time.Second, "23:30 – Pomodoro"},
+		{90 * time.Minute, 0, "1:30:00 – Pomodoro"},
+		{3 * time.Hour, 30 * time.Minute, "2:30:00 – Pomodoro"},
+	}
+	for _, tt := range tests {
+		cfg := defaultConfig()
+		cfg.Sessions[0].Duration = tt.duration
+		m := newTestModel(t, cfg)
+		m.startTimer(0)
+		m.Timers[0].CurrentTime = tt.current
+		if got := m.windowTitle(); got != tt.want {
+			t.Errorf("title of a %s session %s in = %q, want %q", tt.duration, tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestCompactViewFitsWidth(t *testing.T) {
+	m := newTestModel(t, defaultConfig())
+	tests := []struct {
+		width int
+		want  string
+	}{
+		{80, "Pomodoro 25:00 - terminal too small"},
+		{20, "Pomodoro 25:00"},
+		{10, "25:00"},
+		{3, "25:"},
+	}
+	for _, tt := range tests {
+		m.Width = tt.width
+		if got := compactView(m); got != tt.want {
+			t.Errorf("compactView at width %d = %q, want %q", tt.width, got, tt.want)
+		}
+	}
+}
+
+// fakeNotifier counts the notifications sent through it.
+type fakeNotifier struct {
+	mu                      sync.Mutex
+	notifies, alerts, tones int
+}
+
+func (n *fakeNotifier) Notify(title, message, icon string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.notifies++
+	return nil
+}
+
+func (n *fakeNotifier) Alert(title, message, icon string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.alerts++
+	return nil
+}
+
+func (n *fakeNotifier) Tone(tone Tone) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.tones++
+	return nil
+}
+
+// count returns the number of Alert calls so far.
+func (n *fakeNotifier) count() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.alerts
+}
+
+// send passes msg through m's Update.
+func send(m Model, msg tea.Msg) (Model, tea.Cmd) {
+	next, cmd := m.Update(msg)
+	return next.(Model), cmd
+}
+
+func TestNoTicksAfterCompletion(t *testing.T) {
+	m := newTestModel(t, defaultConfig())
+	mode := m.workIndex()
+	m.startTimer(mode)
+	id, started := m.Timers[mode].TickID, m.Timers[mode].StartedAt
+	d := m.getDurationByIndex(mode)
+
+	// The tick that fills the timer, then the one that ends the session.
+	m, _ = send(m, tickMsg{Mode: mode, ID: id, At: started.Add(d)})
+	m, _ = send(m, tickMsg{Mode: mode, ID: id, At: started.Add(d + time.Second)})
+	if !m.Timers[mode].Done {
+		t.Fatal("the timer did not finish")
+	}
+	m, _ = send(m, progressDoneMsg{Mode: mode})
+	if m.Timers[mode].Status != Idle {
+		t.Fatalf("timer status = %s after completion, want %s", m.Timers[mode].Status, Idle)
+	}
+
+	// A tick of the finished loop still in flight must neither advance the
+	// reset timer nor keep the loop going.
+	m, cmd := send(m, tickMsg{Mode: mode, ID: id, At: started.Add(d + 2*time.Second)})
+	if cmd != nil {
+		t.Error("a tick after completion returned a command")
+	}
+	if got := m.Timers[mode]; got.Status != Idle || got.CurrentTime != 0 || got.Percent != 0 {
+		t.Errorf("timer = %+v after a late tick, want it reset", got)
+	}
+	// Nor may a second completion count the session again.
+	m, _ = send(m, progressDoneMsg{Mode: mode})
+	if m.TodayPomodoros != 1 {
+		t.Errorf("TodayPomodoros = %d, want 1", m.TodayPomodoros)
+	}
+}
+
+func TestStaleTicksAreDropped(t *testing.T) {
+	tests := []struct {
+		name  string
+		stale func(m *Model, mode int) tickMsg
+	}{
+		{"earlier loop", func(m *Model, mode int) tickMsg {
+			old := m.Timers[mode].TickID
+			m.startTimer(mode)
+			return tickMsg{Mode: mode, ID: old}
+		}},
+		{"paused timer", func(m *Model, mode int) tickMsg {
+			m.pauseTimer(mode)
+			return tickMsg{Mode: mode, ID: m.Timers[mode].TickID}
+		}},
+		{"reset timer", func(m *Model, mode int) tickMsg {
+			id := m.Timers[mode].TickID
+			m.resetTimer(mode)
+			return tickMsg{Mode: mode, ID: id}
+		}},
+		{"other timer", func(m *Model, mode int) tickMsg {
+			return tickMsg{Mode: m.breakIndex(), ID: m.Timers[mode].TickID}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.SetTitle = false // the title would change on the first update
+			m := newTestModel(t, cfg)
+			mode := m.workIndex()
+			m.startTimer(mode)
+			msg := tt.stale(&m, mode)
+			msg.At = m.Timers[mode].StartedAt.Add(time.Minute)
+			before := append([]timer(nil), m.Timers...)
+
+			m, cmd := send(m, msg)
+			if cmd != nil {
+				t.Error("a stale tick returned a command")
+			}
+			for i := range m.Timers {
+				if m.Timers[i].CurrentTime != before[i].CurrentTime {
+					t.Errorf("timer %d moved from %s to %s", i, before[i].CurrentTime, m.Timers[i].CurrentTime)
+				}
+			}
+		})
+	}
+}
+
+// failingLogger fails to log every record.
+type failingLogger struct{}
+
+func (failingLogger) Log(record sessionRecord) error {
+	return errors.New("disk full")
+}
+
+func TestLogFailureShowsNotice(t *testing.T) {
+	saved := sessionLog
+	sessionLog = failingLogger{}
+	t.Cleanup(func() { sessionLog = saved })
+
+	m := newTestModel(t, defaultConfig())
+	mode := m.workIndex()
+	finishTimer(&m, mode)
+	msg := m.handleCompletion(mode)()
+	if _, ok := msg.(logFailedMsg); !ok {
+		t.Fatalf("logging the session returned %T, want a logFailedMsg", msg)
+	}
+	m, _ = send(m, msg)
+	if !strings.Contains(m.Notice, "disk full") {
+		t.Errorf("Notice = %q, want the logging error", m.Notice)
+	}
+}
+
+func TestUndoCompletion(t *testing.T) {
+	tests := []struct {
+		name      string
+		logAfter  bool // another record is logged after the completion
+		wantToday int
+		wantLog   int
+	}{
+		{"last record", false, 0, 0},
+		{"record no longer last", true, 1, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t, defaultConfig())
+			mode := m.workIndex()
+			finishTimer(&m, mode)
+			if msg := m.handleCompletion(mode)(); msg != nil {
+				t.Fatalf("logging the session returned %v", msg)
+			}
+			if tt.logAfter {
+				if err := logSession(sessionRecord{Type: ShortBreak, Name: "Short Break", CompletedAt: time.Now().Add(time.Minute)}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cmd := m.undoCompletion()
+			m, _ = send(m, cmd())
+			if m.TodayPomodoros != tt.wantToday || m.CompletedPomodoros != tt.wantToday {
+				t.Errorf("TodayPomodoros, CompletedPomodoros = %d, %d, want %d", m.TodayPomodoros, m.CompletedPomodoros, tt.wantToday)
+			}
+			if tt.logAfter && !strings.Contains(m.Notice, errNotLastRecord.Error()) {
+				t.Errorf("Notice = %q, want the undo error", m.Notice)
+			}
+			records, err := readHistory()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != tt.wantLog {
+				t.Errorf("history has %d records, want %d", len(records), tt.wantLog)
+			}
+		})
+	}
+}
+
+func TestSkipClearsLastRecord(t *testing.T) {
+	m := newTestModel(t, defaultConfig())
+	work := m.workIndex()
+	finishTimer(&m, work)
+	m.handleCompletion(work)()
+	if m.LastRecord == nil {
+		t.Fatal("no LastRecord after a completion")
+	}
+
+	// The break after it is logged as skipped, so the completion is no
+	// longer the last record to undo.
+	m.skip(m.NextMode)
+	m, _ = send(m, key("u"))
+	if m.Confirm != nil || m.Notice != "Nothing to undo" {
+		t.Errorf("u after a skip asked %v with notice %q, want nothing to undo", m.Confirm, m.Notice)
+	}
+}
+
+func TestPausedTimerIgnoresTicks(t *testing.T) {
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+	m := newTestModel(t, defaultConfig())
+	mode := m.workIndex()
+	m, _ = send(m, space)
+	id, started := m.Timers[mode].TickID, m.Timers[mode].StartedAt
+	m, _ = send(m, tickMsg{Mode: mode, ID: id, At: started.Add(time.Second)})
+
+	m, _ = send(m, space)
+	if m.Timers[mode].Status != Paused {
+		t.Fatalf("timer status = %s, want %s", m.Timers[mode].Status, Paused)
+	}
+	elapsed := m.Timers[mode].CurrentTime
+	for i := 2; i <= 5; i++ {
+		var cmd tea.Cmd
+		m, cmd = send(m, tickMsg{Mode: mode, ID: id, At: started.Add(time.Duration(i) * time.Second)})
+		if cmd != nil {
+			t.Errorf("tick %d while paused returned a command", i)
+		}
+	}
+	if got := m.Timers[mode].CurrentTime; got != elapsed {
+		t.Errorf("CurrentTime = %s after ticks while paused, want %s", got, elapsed)
+	}
+
+	// Nor does a tick still in flight when the paused timer is reset.
+	m, _ = send(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m, _ = send(m, tickMsg{Mode: mode, ID: id, At: started.Add(6 * time.Second)})
+	if got := m.Timers[mode]; got.Status != Idle || got.CurrentTime != 0 {
+		t.Errorf("timer = %+v after a tick following the reset, want it reset", got)
+	}
+}
+
+func TestOtherTabResumeRejected(t *testing.T) {
+	action := OtherTabRestart
+	err := action.Set("resume")
+	if err == nil || !strings.Contains(err.Error(), string(OtherTabSwitch)) {
+		t.Errorf("Set(resume) = %v, want an error pointing at switch", err)
+	}
+	if action != OtherTabRestart {
+		t.Errorf("action = %s after a rejected Set, want %s", action, OtherTabRestart)
+	}
+}
+
+func TestSpaceResumesFromOtherTab(t *testing.T) {
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+	right := tea.KeyMsg{Type: tea.KeyRight}
+	for _, action := range []OtherTabAction{OtherTabRestart, OtherTabSwitch, OtherTabIgnore} {
+		t.Run(string(action), func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.OtherTab = action
+			m := newTestModel(t, cfg)
+			mode := m.workIndex()
+			m, _ = send(m, space)
+			m, _ = send(m, space)
+			m, _ = send(m, right)
+			if m.ActiveTab == mode || m.Timers[mode].Status != Paused {
+				t.Fatalf("want the pomodoro paused and another tab viewed, got tab %d, status %s", m.ActiveTab, m.Timers[mode].Status)
+			}
+
+			m, cmd := send(m, space)
+			if m.Timers[mode].Status != Running || cmd == nil {
+				t.Errorf("timer status = %s, want the pomodoro resumed and ticking", m.Timers[mode].Status)
+			}
+			if m.ProgressMode != mode || m.Timers[m.ActiveTab].Status != Idle {
+				t.Error("space started the viewed tab instead of resuming the pomodoro")
+			}
+		})
+	}
+}
+
+func TestSpaceDoesNothingOnStats(t *testing.T) {
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+	m := newTestModel(t, defaultConfig())
+	mode := m.workIndex()
+	m, _ = send(m, space)
+	m, _ = send(m, space)
+	m.Stats = true
+
+	m, _ = send(m, space)
+	if m.Timers[mode].Status != Paused {
+		t.Errorf("timer status = %s after space on the stats tab, want %s", m.Timers[mode].Status, Paused)
+	}
+}
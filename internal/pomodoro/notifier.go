@@ -0,0 +1,133 @@
+package pomodoro
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/gen2brain/beeep"
+)
+
+// Notifier delivers desktop notifications. Alert is used for session
+// completions and may also play a sound; Notify is for quieter heads-ups.
+// Tone plays a short melody of beeps.
+type Notifier interface {
+	Notify(title, message, icon string) error
+	Alert(title, message, icon string) error
+	Tone(tone Tone) error
+}
+
+// Tone is a melody of short beeps, given as their frequencies in Hz.
+type Tone []float64
+
+var (
+	// startTone rises for a session starting.
+	startTone = Tone{523, 659, 784}
+	// endTone falls for a session completing.
+	endTone = Tone{784, 659, 523}
+)
+
+// toneBeep is how long each beep of a Tone lasts, in milliseconds.
+const toneBeep = 120
+
+// playTone beeps the tone on the PC speaker where beeep can drive it, and
+// falls back to a single terminal bell where it cannot.
+func playTone(tone Tone) error {
+	for _, freq := range tone {
+		if err := beeep.Beep(freq, toneBeep); err != nil {
+			fmt.Fprint(os.Stdout, "\a")
+			return err
+		}
+	}
+	return nil
+}
+
+// NewNotifier returns the Notifier for backend. Backends that rely on an
+// external command report an error if it cannot be found, alongside a
+// beeep notifier to fall back to.
+func NewNotifier(backend NotifyBackend) (Notifier, error) {
+	switch backend {
+	case NotifyNone:
+		return noneNotifier{}, nil
+	case NotifySend, NotifyOsascript:
+		if _, err := exec.LookPath(string(backend)); err != nil {
+			return beeepNotifier{}, fmt.Errorf("%s backend unavailable, using beeep: %w", backend, err)
+		}
+		if backend == NotifySend {
+			return notifySendNotifier{}, nil
+		}
+		return osascriptNotifier{}, nil
+	default:
+		return beeepNotifier{}, nil
+	}
+}
+
+type beeepNotifier struct{}
+
+func (beeepNotifier) Notify(title, message, icon string) error {
+	return beeep.Notify(title, message, icon)
+}
+
+func (beeepNotifier) Alert(title, message, icon string) error {
+	return beeep.Alert(title, message, icon)
+}
+
+func (beeepNotifier) Tone(tone Tone) error {
+	return playTone(tone)
+}
+
+// notifySendNotifier uses libnotify's notify-send, sending alerts with
+// critical urgency.
+type notifySendNotifier struct{}
+
+func (notifySendNotifier) send(urgency, title, message, icon string) error {
+	args := []string{"-u", urgency}
+	if icon != "" {
+		args = append(args, "-i", icon)
+	}
+	args = append(args, title, message)
+	return exec.Command("notify-send", args...).Run()
+}
+
+func (n notifySendNotifier) Notify(title, message, icon string) error {
+	return n.send("normal", title, message, icon)
+}
+
+func (n notifySendNotifier) Alert(title, message, icon string) error {
+	return n.send("critical", title, message, icon)
+}
+
+func (notifySendNotifier) Tone(tone Tone) error {
+	return playTone(tone)
+}
+
+// osascriptNotifier uses AppleScript's display notification on macOS, which
+// has no support for custom icons.
+type osascriptNotifier struct{}
+
+func (osascriptNotifier) send(title, message, sound string) error {
+	script := fmt.Sprintf("display notification %s with title %s", strconv.Quote(message), strconv.Quote(title))
+	if sound != "" {
+		script += " sound name " + strconv.Quote(sound)
+	}
+	return exec.Command("osascript", "-e", script).Run()
+}
+
+func (n osascriptNotifier) Notify(title, message, _ string) error {
+	return n.send(title, message, "")
+}
+
+func (n osascriptNotifier) Alert(title, message, _ string) error {
+	return n.send(title, message, "default")
+}
+
+func (osascriptNotifier) Tone(tone Tone) error {
+	return playTone(tone)
+}
+
+type noneNotifier struct{}
+
+func (noneNotifier) Notify(title, message, icon string) error { return nil }
+func (noneNotifier) Alert(title, message, icon string) error  { return nil }
+func (noneNotifier) Tone(tone Tone) error                     { return nil }
@@ -0,0 +1,57 @@
+package pomodoro
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+)
+
+func TestQuietHoursSuppressAlerts(t *testing.T) {
+	now := time.Now()
+	clock := time.Duration(now.Hour())*time.Hour + time.Duration(now.Minute())*time.Minute
+	window := func(from, to time.Duration) QuietHours {
+		day := 24 * time.Hour
+		return QuietHours{Start: (clock + from + day) % day, End: (clock + to + day) % day, On: true}
+	}
+	tests := []struct {
+		name  string
+		quiet QuietHours
+		want  int
+	}{
+		{"inside quiet hours", window(-time.Hour, time.Hour), 0},
+		{"outside quiet hours", window(time.Hour, 2*time.Hour), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolate(t)
+			cfg := defaultConfig()
+			cfg.Sessions[0].Duration = time.Second
+			cfg.DoneHold = 0
+			cfg.QuietHours = tt.quiet
+			notifier := &fakeNotifier{}
+			tm := teatest.NewTestModel(t, NewModel(cfg, notifier), teatest.WithInitialTermSize(80, 24))
+
+			tm.Send(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+			// The completion shows in the app, quiet hours or not.
+			teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+				return bytes.Contains(out, []byte("complete!"))
+			}, teatest.WithDuration(5*time.Second))
+			// The alert goes out alongside the toast, so give it a moment to
+			// arrive, or in quiet hours not to, before quitting drops it.
+			for deadline := time.Now().Add(time.Second); notifier.count() < max(tt.want, 1) && time.Now().Before(deadline); {
+				time.Sleep(10 * time.Millisecond)
+			}
+			if err := tm.Quit(); err != nil {
+				t.Fatal(err)
+			}
+			tm.WaitFinished(t, teatest.WithFinalTimeout(5*time.Second))
+
+			if got := notifier.count(); got != tt.want {
+				t.Errorf("Alert called %d times, want %d", got, tt.want)
+			}
+		})
+	}
+}
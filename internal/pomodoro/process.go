@@ -0,0 +1,25 @@
+package pomodoro
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// processPoll is how often -watch checks for its process.
+const processPoll = 5 * time.Second
+
+// processMsg reports whether the watched process was running at a check, or
+// the error that kept it from being checked.
+type processMsg struct {
+	Running bool
+	Err     error
+}
+
+// processCheck schedules the next check for the process called name.
+func processCheck(name string) tea.Cmd {
+	return tea.Tick(processPoll, func(time.Time) tea.Msg {
+		running, err := processRunning(name)
+		return processMsg{Running: running, Err: err}
+	})
+}
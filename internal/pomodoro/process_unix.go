@@ -0,0 +1,20 @@
+//go:build !windows
+
+package pomodoro
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// processRunning reports whether a process called exactly name is running,
+// asking pgrep.
+func processRunning(name string) (bool, error) {
+	err := exec.Command("pgrep", "-x", name).Run()
+	var exit *exec.ExitError
+	if errors.As(err, &exit) && exit.ExitCode() == 1 {
+		// pgrep exits 1 when nothing matched.
+		return false, nil
+	}
+	return err == nil, err
+}
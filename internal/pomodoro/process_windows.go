@@ -0,0 +1,21 @@
+//go:build windows
+
+package pomodoro
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// processRunning reports whether a process with the image name name, with
+// or without ".exe", is running, asking tasklist.
+func processRunning(name string) (bool, error) {
+	if !strings.HasSuffix(strings.ToLower(name), ".exe") {
+		name += ".exe"
+	}
+	out, err := exec.Command("tasklist", "/FI", "IMAGENAME eq "+name, "/FO", "CSV", "/NH").Output()
+	if err != nil {
+		return false, err
+	}
+	return strings.Contains(strings.ToLower(string(out)), `"`+strings.ToLower(name)+`"`), nil
+}
@@ -0,0 +1,112 @@
+package pomodoro
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Review prints yesterday's sessions of the current profile, with ProfileStats,
+// to w.
+func Review(w io.Writer, cfg Config) error {
+	records, err := readHistory()
+	if err != nil {
+		return err
+	}
+	if cfg.ProfileStats {
+		records = filterProfile(records, cfg.Profile)
+	}
+	return printReview(w, records, time.Now())
+}
+
+// printReview writes a look back at the day before the day of now: the
+// focus time and sessions it had, how often they were interrupted, and each
+// session with its task and note.
+func printReview(w io.Writer, records []sessionRecord, now time.Time) error {
+	yesterday := statsDay(now).AddDate(0, 0, -1)
+
+	var day []sessionRecord
+	for _, record := range records {
+		if sameDay(record.CompletedAt, yesterday) {
+			day = append(day, record)
+		}
+	}
+	if _, err := fmt.Fprintf(w, "%s\n\n", yesterday.Format("Monday, 2 January")); err != nil {
+		return err
+	}
+	if len(day) == 0 {
+		_, err := fmt.Fprintln(w, "No sessions.")
+		return err
+	}
+
+	pomodoros, abandoned, interruptions := 0, 0, 0
+	var focus, paused time.Duration
+	for _, record := range day {
+		switch {
+		case record.Abandoned:
+			abandoned++
+		case record.Type == Work:
+			pomodoros++
+			focus += time.Duration(record.DurationSeconds) * time.Second
+		}
+		interruptions += len(record.Pauses)
+		paused += recordPaused(record)
+	}
+
+	fmt.Fprintf(w, "%s, %s of focus\n", counted(pomodoros, "pomodoro", "pomodoros"), focus)
+	if abandoned > 0 {
+		fmt.Fprintf(w, "%s abandoned\n", counted(abandoned, "session", "sessions"))
+	}
+	if interruptions > 0 {
+		fmt.Fprintf(w, "%s, %s paused\n", counted(interruptions, "interruption", "interruptions"), paused)
+	} else if paused > 0 {
+		fmt.Fprintf(w, "%s paused\n", paused)
+	}
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, record := range day {
+		var details []string
+		if record.Abandoned {
+			details = append(details, "abandoned")
+			if record.Reason != "" {
+				details = append(details, record.Reason)
+			}
+		}
+		for _, detail := range []string{record.Task, record.Note} {
+			if detail != "" {
+				details = append(details, detail)
+			}
+		}
+		line := record.StartedAt.Format("15:04") + "-" + record.CompletedAt.Format("15:04") + "\t" + tr(record.Name)
+		if len(details) > 0 {
+			line += "\t" + strings.Join(details, " - ")
+		}
+		fmt.Fprintln(tw, line)
+	}
+	return tw.Flush()
+}
+
+// recordPaused is how long the session of record spent paused, from the
+// pauses VerboseLog logged with it. It is not made up from the elapsed time:
+// the session ended at its last tick, a second or two before CompletedAt,
+// which would count as a pause of every session.
+func recordPaused(record sessionRecord) time.Duration {
+	var paused time.Duration
+	for _, p := range record.Pauses {
+		if !p.ResumedAt.IsZero() {
+			paused += p.ResumedAt.Sub(p.PausedAt)
+		}
+	}
+	return paused
+}
+
+// counted writes n with the singular or plural noun, e.g. "1 pomodoro".
+func counted(n int, one, many string) string {
+	if n == 1 {
+		return "1 " + one
+	}
+	return fmt.Sprintf("%d %s", n, many)
+}
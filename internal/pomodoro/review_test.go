@@ -0,0 +1,49 @@
+package pomodoro
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordPaused(t *testing.T) {
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.Local)
+	tests := []struct {
+		name   string
+		record sessionRecord
+		want   time.Duration
+	}{
+		{
+			// Completed a moment after the tick that ended it, unpaused.
+			"no pauses logged",
+			sessionRecord{StartedAt: start, CompletedAt: start.Add(25*time.Minute + 2*time.Second), ElapsedSeconds: 1500},
+			0,
+		},
+		{
+			"logged pauses",
+			sessionRecord{
+				StartedAt:      start,
+				CompletedAt:    start.Add(28*time.Minute + 2*time.Second),
+				ElapsedSeconds: 1500,
+				Pauses: []pauseInterval{
+					{PausedAt: start.Add(5 * time.Minute), ResumedAt: start.Add(6 * time.Minute)},
+					{PausedAt: start.Add(10 * time.Minute), ResumedAt: start.Add(12 * time.Minute)},
+				},
+			},
+			3 * time.Minute,
+		},
+		{
+			"pause never resumed",
+			sessionRecord{
+				StartedAt:   start,
+				CompletedAt: start.Add(25 * time.Minute),
+				Pauses:      []pauseInterval{{PausedAt: start.Add(5 * time.Minute)}},
+			},
+			0,
+		},
+	}
+	for _, tt := range tests {
+		if got := recordPaused(tt.record); got != tt.want {
+			t.Errorf("%s: recordPaused = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
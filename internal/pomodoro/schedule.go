@@ -0,0 +1,57 @@
+package pomodoro
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// PrintSchedule writes the next n sessions of the pomodoro cycle, starting
+// from where m is, with the time each would end if run back to back from
+// start.
+func PrintSchedule(w io.Writer, m Model, n int, start time.Time) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	end := start
+	for i := 1; i <= n; i++ {
+		mode := m.NextMode
+		duration := m.getDurationByIndex(mode)
+		end = end.Add(duration)
+		fmt.Fprintf(tw, "%d.\t%s\t%s\t%s\n", i, tr(m.Tabs[mode].Name), duration, end.Format("15:04"))
+		m.advanceCycle(mode)
+	}
+	return tw.Flush()
+}
+
+// PrintSimulation writes the sessions of the pomodoro cycle that fit back to
+// back in window from start, starting from where m is, with when each would
+// start and end, followed by the totals.
+func PrintSimulation(w io.Writer, m Model, window time.Duration, start time.Time) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	var pomodoros, breaks int
+	var focus, rest time.Duration
+	at, end := start, start.Add(window)
+	for i := 1; ; i++ {
+		mode := m.NextMode
+		duration := m.getDurationByIndex(mode)
+		if duration <= 0 || at.Add(duration).After(end) {
+			break
+		}
+		fmt.Fprintf(tw, "%d.\t%s\t%s\t%s-%s\n", i, tr(m.Tabs[mode].Name), duration, at.Format("15:04"), at.Add(duration).Format("15:04"))
+		if m.Tabs[mode].Kind == Work {
+			pomodoros++
+			focus += duration
+		} else {
+			breaks++
+			rest += duration
+		}
+		at = at.Add(duration)
+		m.advanceCycle(mode)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d pomodoros (%s) and %d breaks (%s) in %s, %s left over\n", pomodoros, focus, breaks, rest, window, end.Sub(at))
+	return err
+}
@@ -0,0 +1,84 @@
+package pomodoro
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SessionLogger records finished sessions somewhere they can be read back
+// or collected from.
+type SessionLogger interface {
+	Log(record sessionRecord) error
+}
+
+// sessionLog is where the app logs its sessions, chosen with -log-to.
+var sessionLog SessionLogger = fileLogger{}
+
+// newSessionLogger returns the SessionLogger for target. A target that
+// cannot be reached reports an error alongside the history file logger to
+// fall back to.
+func newSessionLogger(target LogTarget) (SessionLogger, error) {
+	if target == LogFile {
+		return fileLogger{}, nil
+	}
+
+	syslog, err := newSyslogLogger()
+	if err != nil {
+		return fileLogger{}, fmt.Errorf("syslog unavailable, logging to the history file: %w", err)
+	}
+	if target == LogBoth {
+		return bothLogger{fileLogger{}, syslog}, nil
+	}
+	return syslog, nil
+}
+
+// SetLogTarget sets where sessions are logged to target. A target that cannot
+// be reached leaves them logged to the history file and reports an error.
+func SetLogTarget(target LogTarget) error {
+	var err error
+	sessionLog, err = newSessionLogger(target)
+	return err
+}
+
+// fileLogger appends to the history log, which the totals, goals and
+// calendar are read from.
+type fileLogger struct{}
+
+func (fileLogger) Log(record sessionRecord) error {
+	return logSession(record)
+}
+
+// bothLogger logs to every one of its loggers, returning the first error.
+type bothLogger []SessionLogger
+
+func (loggers bothLogger) Log(record sessionRecord) error {
+	var first error
+	for _, logger := range loggers {
+		if err := logger.Log(record); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// syslogMessage is the line sent to syslog for record: an event name for
+// grepping followed by the record as it would appear in the history log,
+// e.g. `completed {"version":3,"type":"pomodoro",...}`.
+func syslogMessage(record sessionRecord) (string, error) {
+	record.Version = historyVersion
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", err
+	}
+
+	event := "completed"
+	switch {
+	case record.skippedBreak():
+		event = "break_skipped"
+	case record.Skipped:
+		event = "skipped"
+	case record.Abandoned:
+		event = "abandoned"
+	}
+	return event + " " + string(data), nil
+}
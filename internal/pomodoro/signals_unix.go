@@ -0,0 +1,15 @@
+//go:build !windows
+
+package pomodoro
+
+import (
+	"os"
+	"syscall"
+)
+
+// daemonSignals maps the signals a daemon responds to onto key presses:
+// SIGUSR1 starts or pauses the session and SIGUSR2 resets it.
+var daemonSignals = map[os.Signal]string{
+	syscall.SIGUSR1: " ",
+	syscall.SIGUSR2: "r",
+}
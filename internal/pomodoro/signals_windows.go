@@ -0,0 +1,9 @@
+//go:build windows
+
+package pomodoro
+
+import "os"
+
+// daemonSignals is empty on Windows, which has no user signals; the daemon
+// can only be stopped there.
+var daemonSignals = map[os.Signal]string{}
@@ -0,0 +1,133 @@
+package pomodoro
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// statsRows is how many of today's sessions the stats tab lists, the latest
+// ones.
+const statsRows = 8
+
+// dayStats is what the stats tab shows of the sessions of a day.
+type dayStats struct {
+	Pomodoros               int
+	Focus                   time.Duration
+	ShortBreaks, LongBreaks int
+	SkippedBreaks           int
+	// Sessions are the day's records, oldest first.
+	Sessions []sessionRecord
+}
+
+// statsMsg carries the stats of today, read from the history log when the
+// stats tab is entered.
+type statsMsg struct {
+	Stats dayStats
+	Err   error
+}
+
+// todayStats adds up the records of the day of now.
+func todayStats(records []sessionRecord, now time.Time) dayStats {
+	var stats dayStats
+	for _, record := range records {
+		if !sameDay(record.CompletedAt, now) {
+			continue
+		}
+		stats.Sessions = append(stats.Sessions, record)
+		switch {
+		case record.skippedBreak():
+			stats.SkippedBreaks++
+		case record.Abandoned:
+		case record.Type == Work:
+			stats.Pomodoros++
+			stats.Focus += time.Duration(record.DurationSeconds) * time.Second
+		case record.Type == ShortBreak:
+			stats.ShortBreaks++
+		case record.Type == LongBreak:
+			stats.LongBreaks++
+		}
+	}
+	return stats
+}
+
+// loadStats reads today's stats from the history log, counting the same
+// sessions as the totals do.
+func (m Model) loadStats() tea.Cmd {
+	profile, profileStats, cleanOnly := m.Profile, m.ProfileStats, m.CleanOnly
+	return func() tea.Msg {
+		records, err := readHistory()
+		if profileStats {
+			records = filterProfile(records, profile)
+		}
+		if cleanOnly {
+			records = filterClean(records)
+		}
+		return statsMsg{Stats: todayStats(records, time.Now()), Err: err}
+	}
+}
+
+// statsView shows the stats tab: today's totals, breaks taken against
+// skipped, the goal over the last days and the latest sessions.
+func statsView(m Model) string {
+	stats := m.DayStats
+	lines := []string{
+		trf("%d pomodoros, %d minutes of focus", stats.Pomodoros, int(stats.Focus.Minutes())),
+		hintStyle.Render(trf("Work: %d, Short: %d, Long: %d", stats.Pomodoros, stats.ShortBreaks, stats.LongBreaks)),
+		hintStyle.Render(trf("Breaks: %d taken, %d skipped", stats.ShortBreaks+stats.LongBreaks, stats.SkippedBreaks)),
+	}
+	if m.GoalDays > 0 {
+		lines = append(lines, hintStyle.Render(trf("Goal met %d of the last %d days", m.GoalDaysMet, m.GoalDays)))
+	}
+
+	if len(stats.Sessions) == 0 {
+		return strings.Join(lines, "\n") + "\n\n" + hintStyle.Render(tr("No sessions today"))
+	}
+	var times, names, details []string
+	for _, record := range stats.Sessions[max(len(stats.Sessions)-statsRows, 0):] {
+		times = append(times, record.StartedAt.Format("15:04")+"-"+record.CompletedAt.Format("15:04")+"  ")
+		names = append(names, tr(record.Name)+"  ")
+		details = append(details, sessionDetails(record))
+	}
+	// The window trims trailing spaces off each line before centering it,
+	// so pad the details out with blank braille cells to keep the rows
+	// lined up, as bigClock does.
+	width := 0
+	for _, detail := range details {
+		width = max(width, lipgloss.Width(detail))
+	}
+	for i, detail := range details {
+		details[i] = detail + strings.Repeat("\u2800", width-lipgloss.Width(detail))
+	}
+	rows := lipgloss.JoinHorizontal(lipgloss.Top,
+		strings.Join(times, "\n"),
+		strings.Join(names, "\n"),
+		hintStyle.Render(strings.Join(details, "\n")),
+	)
+	return strings.Join(lines, "\n") + "\n\n" + rows
+}
+
+// sessionDetails describes how a session in the stats tab went, e.g.
+// "3 interruptions, paused 4m0s", or that it did not complete.
+func sessionDetails(record sessionRecord) string {
+	var details []string
+	switch {
+	case record.Skipped:
+		details = append(details, tr("skipped"))
+	case record.Abandoned:
+		details = append(details, tr("abandoned"))
+	}
+	if n := len(record.Pauses); n > 0 {
+		format := "%d interruptions"
+		if n == 1 {
+			format = "%d interruption"
+		}
+		details = append(details, trf(format, n))
+	}
+	if paused := recordPaused(record).Round(time.Second); paused > 0 {
+		details = append(details, trf("paused %s", paused))
+	}
+	return strings.Join(details, ", ")
+}
@@ -0,0 +1,27 @@
+//go:build !windows
+
+package pomodoro
+
+import "log/syslog"
+
+// syslogLogger sends sessions to the local syslog daemon, or journald
+// through its syslog socket, tagged "pomodoro".
+type syslogLogger struct {
+	w *syslog.Writer
+}
+
+func newSyslogLogger() (SessionLogger, error) {
+	w, err := syslog.New(syslog.LOG_INFO|syslog.LOG_USER, "pomodoro")
+	if err != nil {
+		return nil, err
+	}
+	return syslogLogger{w}, nil
+}
+
+func (l syslogLogger) Log(record sessionRecord) error {
+	msg, err := syslogMessage(record)
+	if err != nil {
+		return err
+	}
+	return l.w.Info(msg)
+}
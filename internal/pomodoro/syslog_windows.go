@@ -0,0 +1,11 @@
+//go:build windows
+
+package pomodoro
+
+import "errors"
+
+// newSyslogLogger always fails on Windows, which has no syslog; sessions
+// are logged to the history file there.
+func newSyslogLogger() (SessionLogger, error) {
+	return nil, errors.New("syslog is not available on Windows")
+}
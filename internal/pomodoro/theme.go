@@ -0,0 +1,69 @@
+package pomodoro
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is the set of colors the UI is drawn in.
+type Theme struct {
+	// Border colors the lines of the tabs and the window.
+	Border lipgloss.AdaptiveColor
+	// Special marks what is running or picked: the running tab, toasts and
+	// the highlighted task.
+	Special lipgloss.AdaptiveColor
+	// Highlight and HighlightEnd are the ends of the progress bar's
+	// gradient.
+	Highlight, HighlightEnd string
+}
+
+// defaultTheme is the theme used unless -theme picks another.
+const defaultTheme = "purple"
+
+// themes are the themes -theme can pick by name.
+var themes = map[string]Theme{
+	"purple": {
+		Border:       lipgloss.AdaptiveColor{Light: "#874BFD", Dark: "#7D56F4"},
+		Special:      lipgloss.AdaptiveColor{Light: "#43BF6D", Dark: "#73F59F"},
+		Highlight:    "#5A56E0",
+		HighlightEnd: "#EE6FF8",
+	},
+	"forest": {
+		Border:       lipgloss.AdaptiveColor{Light: "#2E7D32", Dark: "#4CAF50"},
+		Special:      lipgloss.AdaptiveColor{Light: "#F9A825", Dark: "#FFD54F"},
+		Highlight:    "#1B5E20",
+		HighlightEnd: "#9CCC65",
+	},
+	"mono": {
+		Border:       lipgloss.AdaptiveColor{Light: "#5C5C5C", Dark: "#9E9E9E"},
+		Special:      lipgloss.AdaptiveColor{Light: "#9E9E9E", Dark: "#FFFFFF"},
+		Highlight:    "#626262",
+		HighlightEnd: "#DDDDDD",
+	},
+}
+
+// SetTheme redraws the styles in the colors of the theme called name. An
+// unknown name keeps defaultTheme and is reported in the error.
+func SetTheme(name string) error {
+	t, ok := themes[name]
+	var err error
+	if !ok {
+		names := make([]string, 0, len(themes))
+		for name := range themes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		t = themes[defaultTheme]
+		err = fmt.Errorf("unknown theme %q, want one of %s; using %s", name, strings.Join(names, ", "), defaultTheme)
+	}
+
+	theme = t
+	inactiveTabStyle = inactiveTabStyle.BorderForeground(t.Border)
+	activeTabStyle = activeTabStyle.BorderForeground(t.Border)
+	windowStyle = windowStyle.BorderForeground(t.Border)
+	toastStyle = toastStyle.Background(t.Special)
+	return err
+}
@@ -0,0 +1,132 @@
+package pomodoro
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// todoPriority matches the priority a todo.txt task may open with, e.g.
+// "(A) ".
+var todoPriority = regexp.MustCompile(`^\([A-Z]\) `)
+
+// readTodo returns the pending tasks of the todo.txt file at path, as their
+// lines. Completed tasks, which start with "x ", and blank lines are left out.
+func readTodo(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "x ") {
+			continue
+		}
+		tasks = append(tasks, line)
+	}
+	return tasks, scanner.Err()
+}
+
+// todoText is a task line without its priority, for showing it.
+func todoText(task string) string {
+	return todoPriority.ReplaceAllString(task, "")
+}
+
+// todoLabel is what names a task across sessions: its text without the
+// pomo: and est: tags, which change as it is worked on.
+func todoLabel(task string) string {
+	var fields []string
+	for _, field := range strings.Fields(todoText(task)) {
+		if !strings.HasPrefix(field, "pomo:") && !strings.HasPrefix(field, "est:") {
+			fields = append(fields, field)
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+// todoEstimate returns the pomodoros task is estimated to take, from its
+// est: tag.
+func todoEstimate(task string) (int, bool) {
+	for _, field := range strings.Fields(task) {
+		if n, ok := strings.CutPrefix(field, "est:"); ok {
+			if estimate, err := strconv.Atoi(n); err == nil {
+				return estimate, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// setEstimate sets the est: tag of task to estimate pomodoros, replacing
+// any it had, or removes the tag if estimate is zero.
+func setEstimate(task string, estimate int) string {
+	var fields []string
+	for _, field := range strings.Fields(task) {
+		if !strings.HasPrefix(field, "est:") {
+			fields = append(fields, field)
+		}
+	}
+	if estimate > 0 {
+		fields = append(fields, "est:"+strconv.Itoa(estimate))
+	}
+	return strings.Join(fields, " ")
+}
+
+// taskCounts counts the completed pomodoros in records by their task.
+func taskCounts(records []sessionRecord) map[string]int {
+	counts := make(map[string]int)
+	for _, record := range records {
+		if record.completedPomodoro() && record.Task != "" {
+			counts[record.Task]++
+		}
+	}
+	return counts
+}
+
+// countPomodoro adds one to the pomo: tag of task, adding the tag if it has
+// none, e.g. "write the report pomo:2".
+func countPomodoro(task string) string {
+	fields := strings.Fields(task)
+	for i, field := range fields {
+		if n, ok := strings.CutPrefix(field, "pomo:"); ok {
+			if count, err := strconv.Atoi(n); err == nil {
+				fields[i] = "pomo:" + strconv.Itoa(count+1)
+				return strings.Join(fields, " ")
+			}
+		}
+	}
+	return task + " pomo:1"
+}
+
+// completeTask marks task done on the day of now, dropping its priority as
+// todo.txt completed tasks do not have one.
+func completeTask(task string, now time.Time) string {
+	return "x " + now.Local().Format("2006-01-02") + " " + todoText(task)
+}
+
+// updateTodo replaces the first line of the todo.txt file at path that is
+// task with updated, leaving the rest of the file as it was. Nothing is
+// written if the task is no longer in the file.
+func updateTodo(path, task, updated string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	for i, line := range lines {
+		text := strings.TrimRight(line, "\r\n")
+		if text == task {
+			lines[i] = updated + line[len(text):]
+			return copyFile(path, []byte(strings.Join(lines, "")))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package pomodoro
+
+import "time"
+
+// timerChange is a transition of one timer between two models.
+type timerChange int
+
+const (
+	timerStarted timerChange = iota
+	timerPaused
+	timerResumed
+	timerRestarted
+	timerReset
+	timerCompleted
+)
+
+// changes reports how each timer changed from prev to m, indexed by mode,
+// in the order the changes happened. Timers that did not change are left
+// out.
+func (m Model) changes(prev Model) map[int][]timerChange {
+	changes := make(map[int][]timerChange)
+	for i, t := range m.Timers {
+		before := prev.Timers[i]
+		// A completion is told by the count of them rather than by the
+		// timer, which AutoAdvance may have started again on the same tab.
+		if m.Completions > prev.Completions && m.LastMode == i {
+			changes[i] = append(changes[i], timerCompleted)
+			before = timer{Status: Idle}
+		}
+		switch {
+		case before.Status == Idle && t.Status == Running:
+			changes[i] = append(changes[i], timerStarted)
+		case before.Status == Running && t.Status == Paused:
+			changes[i] = append(changes[i], timerPaused)
+		case before.Status == Paused && t.Status == Running:
+			changes[i] = append(changes[i], timerResumed)
+		case before.Status == Running && t.Status == Running && !t.StartedAt.Equal(before.StartedAt):
+			changes[i] = append(changes[i], timerRestarted)
+		case before.Status != Idle && t.Status == Idle:
+			changes[i] = append(changes[i], timerReset)
+		}
+	}
+	return changes
+}
+
+// transcriptFormats are the transcript lines for each timerChange.
+var transcriptFormats = map[timerChange]string{
+	timerStarted:   "%s started",
+	timerPaused:    "%s paused",
+	timerResumed:   "%s resumed",
+	timerRestarted: "%s restarted",
+	timerReset:     "%s reset",
+	timerCompleted: "%s completed",
+}
+
+// transcript describes how the timers changed from prev to m, one
+// timestamped line per change, e.g. "09:25:00 Pomodoro started".
+func (m Model) transcript(prev Model) []string {
+	stamp := time.Now().Format("15:04:05 ")
+	changes := m.changes(prev)
+	var lines []string
+	for i := range m.Timers {
+		for _, change := range changes[i] {
+			lines = append(lines, stamp+trf(transcriptFormats[change], tr(m.Tabs[i].Name)))
+		}
+	}
+
+	if m.Notice != "" && m.Notice != prev.Notice {
+		lines = append(lines, stamp+m.Notice)
+	}
+	return lines
+}
+
+// finalTranscript is the last transcript line, recording the session that
+// was still on when the app quit.
+func (m Model) finalTranscript() string {
+	if m.Timers[m.ProgressMode].Status == Idle {
+		return ""
+	}
+
+	_, remaining := m.tabProgress(m.ProgressMode)
+	return time.Now().Format("15:04:05 ") + trf("%s stopped with %s left", tr(m.Tabs[m.ProgressMode].Name), remaining)
+}
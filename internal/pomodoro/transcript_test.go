@@ -0,0 +1,33 @@
+package pomodoro
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestChangesCompletionRestartingSameTab(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.TabOrder = TabOrder{Work}
+	cfg.ApplyTabs()
+	cfg.AutoAdvance = true
+	m := newTestModel(t, cfg)
+	finishTimer(&m, 0)
+
+	prev := m
+	prev.Timers = append([]timer(nil), m.Timers...)
+	updated, _ := m.update(progressDoneMsg{Mode: 0})
+	m = updated.(Model)
+	if m.Timers[0].Status != Running {
+		t.Fatalf("timer status = %s, want the next pomodoro %s", m.Timers[0].Status, Running)
+	}
+
+	want := []timerChange{timerCompleted, timerStarted}
+	if got := m.changes(prev)[0]; !reflect.DeepEqual(got, want) {
+		t.Errorf("changes = %v, want %v", got, want)
+	}
+	lines := m.transcript(prev)
+	if len(lines) < 2 || !strings.HasSuffix(lines[0], "Pomodoro completed") || !strings.HasSuffix(lines[1], "Pomodoro started") {
+		t.Errorf("transcript = %q, want the pomodoro completed, then started", lines)
+	}
+}
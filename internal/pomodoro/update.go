@@ -0,0 +1,710 @@
+package pomodoro
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// update changes the timers in place, so keep a copy to compare with.
+	prev := m
+	prev.Timers = append([]timer(nil), m.Timers...)
+
+	updated, cmd := m.update(msg)
+	next := updated.(Model)
+	if hooks := next.runHooks(prev); hooks != nil {
+		cmd = tea.Batch(cmd, hooks)
+	}
+	if next.Transcript {
+		if lines := next.transcript(prev); len(lines) > 0 {
+			cmd = tea.Batch(tea.Println(strings.Join(lines, "\n")), cmd)
+		}
+	}
+	if next.SetTitle {
+		if title := next.windowTitle(); title != next.Title {
+			next.Title = title
+			cmd = tea.Batch(cmd, tea.SetWindowTitle(title))
+		}
+	}
+	return next, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if event, ok := focusEvent(msg); ok {
+		msg = event
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		m.LastActivity = time.Now()
+		m.Notice = ""
+
+		if m.Confirm != nil {
+			pending := m.Confirm
+			m.Confirm = nil
+			switch msg.String() {
+			case "ctrl+c":
+				return m, m.quit()
+			case "y":
+				cmd := pending.Action(&m)
+				return m, cmd
+			}
+			return m, nil
+		}
+
+		if m.AskingReason {
+			switch msg.Type {
+			case tea.KeyCtrlC:
+				return m, m.quit()
+			case tea.KeyEnter, tea.KeyEsc:
+				reason := m.Reason.Value()
+				if msg.Type == tea.KeyEsc {
+					reason = ""
+				}
+				m.AskingReason = false
+				m.Reason.Blur()
+				cmd := m.abandon(m.focusedTimer(), reason)
+				m.resetTimer(m.focusedTimer())
+				return m, cmd
+			}
+			var cmd tea.Cmd
+			m.Reason, cmd = m.Reason.Update(msg)
+			return m, cmd
+		}
+
+		if m.Picking {
+			switch msg.String() {
+			case "ctrl+c":
+				return m, m.quit()
+			case "up", "k":
+				m.Pick = max(m.Pick-1, 0)
+			case "down", "j":
+				m.Pick = min(m.Pick+1, len(m.Tasks)-1)
+			case "enter":
+				m.Task = m.Tasks[m.Pick]
+				m.Picking = false
+			case "backspace":
+				m.Task = ""
+				m.Picking = false
+			case "esc":
+				m.Picking = false
+			case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9":
+				// Estimate the highlighted task at that many pomodoros.
+				task := m.Tasks[m.Pick]
+				updated := setEstimate(task, int(msg.Runes[0]-'0'))
+				m.Tasks[m.Pick] = updated
+				if m.Task == task {
+					m.Task = updated
+				}
+				path := m.TodoFile
+				return m, func() tea.Msg {
+					updateTodo(path, task, updated)
+					return nil
+				}
+			}
+			return m, nil
+		}
+
+		if m.Noting {
+			switch msg.Type {
+			case tea.KeyCtrlC:
+				return m, m.quit()
+			case tea.KeyEnter:
+				cmd := m.saveNote(m.Note.Value())
+				return m, cmd
+			case tea.KeyEsc:
+				cmd := m.saveNote("")
+				return m, cmd
+			}
+			var cmd tea.Cmd
+			m.Note, cmd = m.Note.Update(msg)
+			return m, cmd
+		}
+
+		if m.Jotting {
+			switch msg.Type {
+			case tea.KeyCtrlC:
+				return m, m.quit()
+			case tea.KeyEsc:
+				m.Jotting = false
+				m.Distraction.Blur()
+				return m, nil
+			case tea.KeyEnter:
+				m.Jotting = false
+				m.Distraction.Blur()
+				note := strings.TrimSpace(m.Distraction.Value())
+				if note == "" {
+					return m, nil
+				}
+				at, session := time.Now(), m.Tabs[m.ProgressMode].Name
+				toast := m.showToast(tr("Noted"))
+				return m, tea.Batch(toast, func() tea.Msg {
+					logDistraction(at, session, note)
+					return nil
+				})
+			}
+			var cmd tea.Cmd
+			m.Distraction, cmd = m.Distraction.Update(msg)
+			return m, cmd
+		}
+
+		if m.EditingGoal {
+			switch msg.Type {
+			case tea.KeyCtrlC:
+				return m, m.quit()
+			case tea.KeyEsc:
+				m.EditingGoal = false
+			case tea.KeyEnter:
+				m.EditingGoal = false
+				goal, err := strconv.Atoi(m.GoalInput)
+				if err != nil {
+					return m, nil
+				}
+				m.Goal = goal
+				today := m.Today
+				return m, func() tea.Msg {
+					saveDayGoal(today, goal)
+					return nil
+				}
+			case tea.KeyBackspace:
+				if m.GoalInput != "" {
+					m.GoalInput = m.GoalInput[:len(m.GoalInput)-1]
+				}
+			case tea.KeyRunes:
+				for _, r := range msg.Runes {
+					if r >= '0' && r <= '9' && len(m.GoalInput) < 3 {
+						m.GoalInput += string(r)
+					}
+				}
+			}
+			return m, nil
+		}
+
+		keypress := msg.String()
+		if m.Locked && (keypress == "q" || keypress == "esc" || keypress == "r" || keypress == "ctrl+r" || keypress == "s") {
+			m.Notice = tr("Locked - press L to unlock")
+			return m, nil
+		}
+		if m.Stats && m.MultiTimer && (keypress == "r" || keypress == "ctrl+r" || keypress == "s" || keypress == "+" || keypress == "=" || keypress == "-") {
+			// These act on the viewed tab's timer, and the stats tab has none.
+			return m, nil
+		}
+		if (keypress == "r" || keypress == "ctrl+r" || keypress == "s") && m.held(m.focusedTimer()) {
+			m.Notice = tr("Strict mode - the pomodoro runs to the end")
+			return m, nil
+		}
+
+		switch keypress {
+		case "ctrl+c":
+			return m, m.quit()
+		case "q", "esc":
+			if m.ConfirmQuit && m.runningPomodoro() {
+				m.Confirm = &confirmation{
+					Prompt: tr("Quit? (y/n)"),
+					Action: func(m *Model) tea.Cmd { return m.quit() },
+				}
+				return m, nil
+			}
+			return m, m.quit()
+		case "?":
+			m.KeysFooter = !m.KeysFooter
+			return m, nil
+		case "A":
+			m.AutoAdvance = !m.AutoAdvance
+			m.AdvancePending = false
+			if m.AutoAdvance {
+				m.Notice = tr("Sessions start one after another")
+			} else {
+				m.Notice = tr("Sessions wait for space to start")
+			}
+			return m, nil
+		case "L":
+			// The lock guards a session against an accidental quit or reset;
+			// ctrl+c still quits.
+			m.Locked = !m.Locked
+			return m, nil
+		case "v":
+			m.Stacked = !m.Stacked
+			return m, nil
+		case "u":
+			if m.LastRecord == nil {
+				m.Notice = tr("Nothing to undo")
+				return m, nil
+			}
+			m.Confirm = &confirmation{
+				Prompt: trf("Undo the %s completed at %s? (y/n)", tr(m.LastRecord.Name), m.LastRecord.CompletedAt.Format("15:04")),
+				Action: (*Model).undoCompletion,
+			}
+			return m, nil
+		case "m":
+			m.Notifications = !m.Notifications
+			return m, nil
+		case "M":
+			m.Sounds = !m.Sounds
+			return m, nil
+		case "N":
+			if m.LastAlert == nil {
+				m.Notice = tr("No notification to replay")
+				return m, nil
+			}
+			return m, tea.Batch(m.sendAlert(*m.LastAlert), m.tone(endTone))
+		case "z":
+			if !m.Snoozable {
+				return m, nil
+			}
+			if m.Snoozes >= m.MaxSnoozes {
+				m.Notice = tr("No snoozes left")
+				return m, nil
+			}
+			m.Snoozes++
+			m.SnoozeID++
+			m.Notice = trf("Snoozed for %s", m.SnoozeFor)
+			id := m.SnoozeID
+			return m, tea.Tick(m.SnoozeFor, func(time.Time) tea.Msg {
+				return snoozeMsg{ID: id}
+			})
+		case "t":
+			if m.TodoFile == "" {
+				return m, nil
+			}
+			tasks, err := readTodo(m.TodoFile)
+			if err != nil {
+				m.Notice = trf("Can't read the tasks: %v", err)
+				return m, nil
+			}
+			if len(tasks) == 0 {
+				m.Notice = tr("No pending tasks")
+				return m, nil
+			}
+			m.Tasks, m.Pick, m.Picking = tasks, 0, true
+			for i, task := range tasks {
+				if task == m.Task {
+					m.Pick = i
+				}
+			}
+			return m, nil
+		case "n":
+			// Jotting a distraction down leaves the session running.
+			m.Jotting = true
+			m.Distraction.SetValue("")
+			cmd := m.Distraction.Focus()
+			return m, cmd
+		case "g":
+			m.EditingGoal = true
+			m.GoalInput = ""
+			if m.Goal > 0 {
+				m.GoalInput = strconv.Itoa(m.Goal)
+			}
+			return m, nil
+		case "r":
+			if m.AskReason && m.Timers[m.focusedTimer()].Status != Idle {
+				m.AskingReason = true
+				m.Reason.SetValue("")
+				cmd := m.Reason.Focus()
+				return m, cmd
+			}
+			m.StartingUntil = time.Time{}
+			m.resetTimer(m.focusedTimer())
+			return m, nil
+		case "s":
+			mode := m.focusedTimer()
+			if m.Timers[mode].Status == Idle {
+				return m, nil
+			}
+			skip := func(m *Model) tea.Cmd { return m.skip(mode) }
+			if m.ConfirmSkip == SkipConfirmAll || m.ConfirmSkip == SkipConfirmWork && m.Tabs[mode].Kind == Work {
+				m.Confirm = &confirmation{
+					Prompt: trf("Skip %s? (y/n)", tr(m.Tabs[mode].Name)),
+					Action: skip,
+				}
+				return m, nil
+			}
+			cmd := skip(&m)
+			return m, cmd
+		case "+", "=", "-":
+			mode := m.focusedTimer()
+			if t := m.Timers[mode]; t.Status == Idle || t.Done {
+				return m, nil
+			}
+			d := adjustStep
+			if keypress == "-" {
+				if m.held(mode) {
+					m.Notice = tr("Strict mode - the pomodoro runs to the end")
+					return m, nil
+				}
+				d = -adjustStep
+			}
+			cmd := m.adjust(mode, d)
+			return m, cmd
+		case "R":
+			// Only the count starts over, whatever session is on.
+			m.CompletedPomodoros = 0
+			m.Notice = tr("Pomodoro count reset")
+			return m, nil
+		case "ctrl+r":
+			mode := m.focusedTimer()
+			if m.Timers[mode].Status == Idle {
+				return m, nil
+			}
+
+			// Restart the current session from zero and keep it running.
+			cmd := m.startTimer(mode)
+			return m, cmd
+		case "c":
+			if !m.idle() {
+				return m, nil
+			}
+
+			// Log an untimed pomodoro as if it had just run for its full duration.
+			work := m.workIndex()
+			m.Timers[work].StartedAt = time.Now().Add(-m.getDurationByIndex(work))
+			cmd := m.handleCompletion(work)
+			return m, cmd
+		case "right", "d", "l", "tab":
+			if m.LockTabs && !m.idle() {
+				return m, nil
+			}
+			if m.ActiveTab == len(m.Tabs)-1 && !m.Stats {
+				m.Stats = true
+				return m, m.loadStats()
+			}
+			m.ActiveTab = min(m.ActiveTab+1, len(m.Tabs)-1)
+			return m, nil
+		case "left", "a", "h", "shift+tab":
+			if m.LockTabs && !m.idle() {
+				return m, nil
+			}
+			if m.Stats {
+				m.Stats = false
+				return m, nil
+			}
+			m.ActiveTab = max(m.ActiveTab-1, 0)
+			return m, nil
+		case " ":
+			if m.starting() {
+				m.StartingUntil = time.Time{}
+				m.Notice = tr("Start cancelled")
+				return m, nil
+			}
+			if m.Stats {
+				return m, nil
+			}
+			if m.MultiTimer {
+				// Every tab runs its own timer independently of the others.
+				switch m.Timers[m.ActiveTab].Status {
+				case Idle:
+					if m.cooldown() > 0 {
+						return m, nil
+					}
+					cmd := m.getReady(m.ActiveTab)
+					return m, cmd
+				case Running:
+					if m.held(m.ActiveTab) {
+						m.Notice = tr("Strict mode - the pomodoro runs to the end")
+						return m, nil
+					}
+					m.pauseTimer(m.ActiveTab)
+					return m, nil
+				case Paused:
+					m.ProgressMode = m.ActiveTab
+					cmd := m.resumeTimer(m.ActiveTab)
+					return m, cmd
+				}
+			}
+
+			if m.Timers[m.ProgressMode].Status == Idle {
+				if m.cooldown() > 0 {
+					return m, nil
+				}
+				if !m.FreeForm {
+					m.ActiveTab = m.NextMode
+				}
+				cmd := m.getReady(m.ActiveTab)
+				return m, cmd
+			}
+
+			if m.ProgressMode == m.ActiveTab {
+				if m.Timers[m.ProgressMode].Status == Running {
+					if m.held(m.ProgressMode) {
+						m.Notice = tr("Strict mode - the pomodoro runs to the end")
+						return m, nil
+					}
+					m.pauseTimer(m.ProgressMode)
+					return m, nil
+				}
+
+				if m.Timers[m.ProgressMode].Status == Paused {
+					cmd := m.resumeTimer(m.ProgressMode)
+					return m, cmd
+				}
+			}
+
+			// A paused session resumes from any tab, whatever OtherTab says
+			// to do about a running one.
+			if m.Timers[m.ProgressMode].Status == Paused {
+				cmd := m.resumeTimer(m.ProgressMode)
+				return m, cmd
+			}
+
+			// The session on another tab is running.
+			switch m.OtherTab {
+			case OtherTabSwitch:
+				m.ActiveTab = m.ProgressMode
+				return m, nil
+			case OtherTabIgnore:
+				m.Notice = trf("%s is running on another tab", tr(m.Tabs[m.ProgressMode].Name))
+				return m, nil
+			}
+
+			// Starting this tab throws away the session on another.
+			target := m.ActiveTab
+			if m.held(m.ProgressMode) {
+				m.Notice = tr("Strict mode - the pomodoro runs to the end")
+				return m, nil
+			}
+			if m.disabled(target) {
+				m.Notice = trf("%s is turned off", tr(m.Tabs[target].Name))
+				return m, nil
+			}
+			if left := m.restLeft(target); left > 0 {
+				m.Notice = trf("Rest a bit longer (%s left)", left)
+				return m, nil
+			}
+			overwrite := func(m *Model) tea.Cmd {
+				m.resetProgress()
+				return m.startTimer(target)
+			}
+			if !m.ConfirmOverwrite {
+				cmd := overwrite(&m)
+				return m, cmd
+			}
+			m.Confirm = &confirmation{
+				Prompt: trf("Discard %s and start %s? (y/n)", tr(m.Tabs[m.ProgressMode].Name), tr(m.Tabs[target].Name)),
+				Action: overwrite,
+			}
+			return m, nil
+
+		}
+	case idleCheckMsg:
+		if m.idle() && time.Since(m.LastActivity) >= m.IdleQuit {
+			return m, m.quit()
+		}
+		return m, idleCheck(m.IdleQuit)
+
+	case dayCheckMsg:
+		if sameDay(m.Today, msg.At) {
+			return m, dayCheck()
+		}
+
+		// A new day starts the daily counters over.
+		var summary tea.Cmd
+		if m.DailySummary {
+			summary = m.dailySummary()
+		}
+		m.Today = msg.At
+		m.TodayPomodoros, m.TodayFocus = 0, 0
+		m.Goal = m.DefaultGoal
+		m.loadTotals()
+		return m, tea.Batch(summary, dayCheck())
+
+	case startMsg:
+		m.ActiveTab = msg.Mode
+		cmd := m.startTimer(msg.Mode)
+		return m, cmd
+
+	case startCountdownMsg:
+		if msg.ID != m.StartID || !m.starting() {
+			return m, nil
+		}
+		if left := time.Until(m.StartingUntil); left > 0 {
+			return m, startCountdown(msg.ID, left)
+		}
+		mode := m.StartingMode
+		m.StartingUntil = time.Time{}
+		if m.Timers[mode].Status != Idle || !m.MultiTimer && !m.idle() {
+			// Another session started in the meantime.
+			return m, nil
+		}
+		cmd := m.startTimer(mode)
+		return m, cmd
+
+	case cooldownMsg:
+		if m.cooldown() > 0 {
+			return m, cooldownTick()
+		}
+		if m.AdvancePending {
+			m.AdvancePending = false
+			return m, m.autoAdvance()
+		}
+		return m, nil
+
+	case blurMsg:
+		if m.PauseOnBlur && m.Timers[m.ProgressMode].Status == Running {
+			m.pauseTimer(m.ProgressMode)
+			m.BlurPaused = true
+		}
+		return m, nil
+
+	case focusMsg:
+		if !m.BlurPaused {
+			return m, nil
+		}
+		m.BlurPaused = false
+		if m.Timers[m.ProgressMode].Status != Paused {
+			return m, nil
+		}
+		cmd := m.resumeTimer(m.ProgressMode)
+		return m, cmd
+
+	case processMsg:
+		if msg.Err != nil {
+			// Stop watching rather than pause on every check.
+			m.Notice = trf("Can't watch for %s: %v", m.WatchProcess, msg.Err)
+			return m, nil
+		}
+		mode := m.ProgressMode
+		switch t := m.Timers[mode]; {
+		case !msg.Running && t.Status == Running && m.Tabs[mode].Kind == Work:
+			m.pauseTimer(mode)
+			m.ProcessPaused = true
+			m.Notice = trf("Paused while %s is not running", m.WatchProcess)
+		case msg.Running && m.ProcessPaused:
+			m.ProcessPaused = false
+			m.Notice = ""
+			if t.Status == Paused {
+				return m, tea.Batch(m.resumeTimer(mode), processCheck(m.WatchProcess))
+			}
+		}
+		return m, processCheck(m.WatchProcess)
+
+	case repaintMsg:
+		return m, repaint(m.Repaint)
+
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		if m.FixedWidth == 0 {
+			for i := range m.ProgressBars {
+				m.ProgressBars[i].Width = barWidth(msg.Width, m.ShowPercent)
+			}
+		}
+		return m, nil
+
+	case tickMsg:
+		// A tick from an earlier loop, or one still in flight after the timer
+		// was paused or reset, must not start the loop again.
+		t := &m.Timers[msg.Mode]
+		if msg.ID != t.TickID || t.Status != Running || t.Done {
+			return m, nil
+		}
+
+		// LastTick is left at the tick that filled the timer, which is when
+		// the session ended.
+		if t.Percent >= 1.0 {
+			t.Percent = 1.0
+			t.Done = true
+			return m, progressDone(msg.Mode, m.DoneHold)
+		}
+
+		if msg.At.Sub(t.LastTick) > sleepThreshold {
+			m.handleSleep(msg.Mode)
+			if t.Status != Running {
+				return m, nil
+			}
+		}
+		t.LastTick = msg.At
+
+		// Ticks arrive late, or not at all while the process is suspended,
+		// so the timer follows the clock rather than counting them.
+		duration := m.getDurationByIndex(msg.Mode)
+		t.CurrentTime = min(max(msg.At.Sub(t.StartedAt)-t.pausedFor(), 0), duration)
+		t.Percent = min(t.CurrentTime.Seconds()/duration.Seconds(), 1)
+		if m.MaxSession > 0 && t.CurrentTime >= m.MaxSession && t.Percent < 1 {
+			return m.stopRunaway(msg.Mode)
+		}
+		reminder := m.preBreakReminder(msg.Mode)
+		return m, tea.Batch(reminder, tick(msg.Mode, msg.ID, m.tickInterval(msg.Mode)))
+
+	case progressDoneMsg:
+		// The timer may have been reset or restarted while its completion
+		// was pending.
+		if !m.Timers[msg.Mode].Done {
+			return m, nil
+		}
+
+		completed := m.completionAlert(msg.Mode)
+		m.LastAlert = &completed
+		notify, tone := m.notifyCompletion(completed), m.tone(endTone)
+		if m.OneShot && msg.Mode == m.StartMode {
+			// Let the notification and log entry finish before quitting.
+			m.Completed = true
+			cmd := m.handleCompletion(msg.Mode)
+			return m, tea.Sequence(tea.Batch(notify, tone, cmd), m.quit())
+		}
+
+		toast := m.showToast(trf("✅ %s complete!", tr(m.Tabs[msg.Mode].Name)))
+		cooldown := m.startCooldown()
+		before := m.TodayPomodoros
+		cmd := m.handleCompletion(msg.Mode)
+		if m.Goal > 0 && before < m.Goal && m.TodayPomodoros >= m.Goal {
+			// Only a pomodoro counts towards the goal, so this one met it.
+			toast = m.showToast("🎉 " + tr("Goal reached!"))
+			notify = tea.Batch(notify, m.notifyCompletion(alert{
+				Title:   tr("Goal reached!"),
+				Message: trf("%d pomodoros today", m.TodayPomodoros),
+				Icon:    m.Icon,
+			}))
+		}
+		next := m.autoAdvance()
+		m.Snoozable, m.Snoozes = m.SnoozeFor > 0, 0
+		return m, tea.Batch(notify, tone, toast, cooldown, cmd, next)
+
+	case snoozeMsg:
+		if msg.ID != m.SnoozeID || !m.Snoozable {
+			return m, nil
+		}
+		return m, tea.Batch(m.notifyCompletion(*m.LastAlert), m.tone(endTone))
+
+	case statsMsg:
+		m.DayStats = msg.Stats
+		if msg.Err != nil {
+			m.Notice = trf("Can't read the history: %v", msg.Err)
+		}
+		return m, nil
+
+	case notifyFailedMsg:
+		m.Notice = trf("Notification failed: %v", msg.Err)
+		return m, nil
+
+	case logFailedMsg:
+		m.Notice = trf("Can't write the history: %v", msg.Err)
+		return m, nil
+
+	case reloadMsg:
+		if msg.Err != nil {
+			return m, m.showToast(trf("Config not reloaded: %s", strings.ReplaceAll(msg.Err.Error(), "\n", "; ")))
+		}
+		m.reload(msg.Config)
+		return m, m.showToast(tr("Config reloaded"))
+
+	case undoneMsg:
+		if msg.Err != nil {
+			// The record is still logged, so its counts stay as well.
+			m.Notice = trf("Can't undo: %v", msg.Err)
+			return m, nil
+		}
+		m.rollBack(msg)
+		return m, nil
+
+	case clearToastMsg:
+		if msg.ID == m.ToastID {
+			m.Toast = ""
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
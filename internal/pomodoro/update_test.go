@@ -0,0 +1,234 @@
+package pomodoro
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// key is the key press of s, a single character or space.
+func key(s string) tea.KeyMsg {
+	if s == " " {
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(s)}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestTickPercent(t *testing.T) {
+	tests := []struct {
+		name        string
+		elapsed     time.Duration // wall time since the start
+		paused      time.Duration
+		wantCurrent time.Duration
+		wantPercent float64
+		wantDone    bool
+	}{
+		{"just started", 0, 0, 0, 0, false},
+		{"a fifth in", 5 * time.Minute, 0, 5 * time.Minute, 0.2, false},
+		{"paused", 15 * time.Minute, 5 * time.Minute, 10 * time.Minute, 0.4, false},
+		{"full", 25 * time.Minute, 0, 25 * time.Minute, 1, true},
+		{"overrun", 40 * time.Minute, 0, 25 * time.Minute, 1, true},
+		{"paused longer than run", 2 * time.Minute, 3 * time.Minute, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t, defaultConfig())
+			mode := m.workIndex()
+			m.startTimer(mode)
+			now := time.Now()
+			timer := &m.Timers[mode]
+			timer.StartedAt = now.Add(-tt.elapsed)
+			// Ticking along, rather than waking up from sleep.
+			timer.LastTick = now.Add(-time.Second)
+			if tt.paused > 0 {
+				timer.Pauses = []pauseInterval{{PausedAt: timer.StartedAt, ResumedAt: timer.StartedAt.Add(tt.paused)}}
+			}
+
+			m, _ = send(m, tickMsg{Mode: mode, ID: timer.TickID, At: now})
+			got := m.Timers[mode]
+			if got.CurrentTime != tt.wantCurrent {
+				t.Errorf("CurrentTime = %s, want %s", got.CurrentTime, tt.wantCurrent)
+			}
+			if got.Percent != tt.wantPercent {
+				t.Errorf("Percent = %v, want %v", got.Percent, tt.wantPercent)
+			}
+
+			// The tick after a full timer ends the session.
+			m, _ = send(m, tickMsg{Mode: mode, ID: got.TickID, At: now.Add(time.Second)})
+			if m.Timers[mode].Done != tt.wantDone {
+				t.Errorf("Done = %t after the next tick, want %t", m.Timers[mode].Done, tt.wantDone)
+			}
+		})
+	}
+}
+
+func TestSleepThroughTimer(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      SleepPolicy
+		gap         time.Duration // since the last tick
+		want        ProgressStatus
+		wantCurrent time.Duration
+		wantNotice  string
+	}{
+		{"late by the threshold", SleepPause, sleepThreshold, Running, time.Minute + sleepThreshold, ""},
+		{"credit", SleepCredit, 10 * time.Minute, Running, 11 * time.Minute, "System slept - session adjusted"},
+		{"pause", SleepPause, 10 * time.Minute, Paused, time.Minute, "System slept - session paused"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.SleepPolicy = tt.policy
+			m := newTestModel(t, cfg)
+			mode := m.workIndex()
+			m.startTimer(mode)
+			timer := &m.Timers[mode]
+			started := time.Now().Add(-time.Hour)
+			timer.StartedAt, timer.LastTick, timer.CurrentTime = started, started.Add(time.Minute), time.Minute
+
+			m, _ = send(m, tickMsg{Mode: mode, ID: timer.TickID, At: timer.LastTick.Add(tt.gap)})
+			got := m.Timers[mode]
+			if got.Status != tt.want {
+				t.Errorf("status = %s, want %s", got.Status, tt.want)
+			}
+			if got.CurrentTime != tt.wantCurrent {
+				t.Errorf("CurrentTime = %s, want %s", got.CurrentTime, tt.wantCurrent)
+			}
+			if m.Notice != tt.wantNotice {
+				t.Errorf("Notice = %q, want %q", m.Notice, tt.wantNotice)
+			}
+			if tt.want == Paused {
+				// The pause starts when the ticks stopped, not on waking.
+				if len(got.Pauses) != 1 || !got.Pauses[0].PausedAt.Equal(started.Add(time.Minute)) {
+					t.Errorf("pauses = %v, want one from the last tick", got.Pauses)
+				}
+			}
+		})
+	}
+}
+
+func TestTabNavigation(t *testing.T) {
+	right, left := tea.KeyMsg{Type: tea.KeyRight}, tea.KeyMsg{Type: tea.KeyLeft}
+	tests := []struct {
+		name      string
+		tab       int
+		stats     bool
+		msg       tea.KeyMsg
+		wantTab   int
+		wantStats bool
+	}{
+		{"right", 0, false, right, 1, false},
+		{"l", 1, false, key("l"), 2, false},
+		{"tab", 0, false, tea.KeyMsg{Type: tea.KeyTab}, 1, false},
+		{"right of the last timer tab", 2, false, right, 2, true},
+		{"right of stats", 2, true, right, 2, true},
+		{"left", 2, false, left, 1, false},
+		{"h", 1, false, key("h"), 0, false},
+		{"shift+tab", 1, false, tea.KeyMsg{Type: tea.KeyShiftTab}, 0, false},
+		{"left of the first tab", 0, false, left, 0, false},
+		{"left of stats", 2, true, left, 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t, defaultConfig())
+			m.ActiveTab, m.Stats = tt.tab, tt.stats
+			m, _ = send(m, tt.msg)
+			if m.ActiveTab != tt.wantTab || m.Stats != tt.wantStats {
+				t.Errorf("tab %d, stats %t; want tab %d, stats %t", m.ActiveTab, m.Stats, tt.wantTab, tt.wantStats)
+			}
+		})
+	}
+}
+
+func TestStartPauseResume(t *testing.T) {
+	tests := []struct {
+		name       string
+		strict     bool
+		keys       string
+		want       ProgressStatus
+		wantPauses int
+	}{
+		{"start", false, " ", Running, 0},
+		{"pause", false, "  ", Paused, 1},
+		{"resume", false, "   ", Running, 1},
+		{"pause again", false, "    ", Paused, 2},
+		{"reset running", false, " r", Idle, 0},
+		{"reset paused", false, "  r", Idle, 0},
+		{"start after reset", false, "  r ", Running, 0},
+		{"strict keeps running", true, "  ", Running, 0},
+		{"strict keeps from reset", true, " r", Running, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.Strict = tt.strict
+			m := newTestModel(t, cfg)
+			mode := m.workIndex()
+			var ids []int
+			for _, k := range tt.keys {
+				var cmd tea.Cmd
+				before := m.Timers[mode].Status
+				m, cmd = send(m, key(string(k)))
+				after := m.Timers[mode]
+				if after.Status == Running && before != Running {
+					// Every start or resume runs a tick loop of its own.
+					if cmd == nil {
+						t.Errorf("%q from %s started no tick loop", k, before)
+					}
+					ids = append(ids, after.TickID)
+				}
+			}
+
+			got := m.Timers[mode]
+			if got.Status != tt.want {
+				t.Errorf("status = %s, want %s", got.Status, tt.want)
+			}
+			if len(got.Pauses) != tt.wantPauses {
+				t.Errorf("%d pauses, want %d", len(got.Pauses), tt.wantPauses)
+			}
+			for i := 1; i < len(ids); i++ {
+				if ids[i] == ids[i-1] {
+					t.Errorf("tick loop %d reused ID %d", i, ids[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMultiTimerResetsViewedTab(t *testing.T) {
+	tests := []struct {
+		name string
+		key  tea.KeyMsg
+		want ProgressStatus
+	}{
+		{"reset", key("r"), Idle},
+		{"restart", tea.KeyMsg{Type: tea.KeyCtrlR}, Running},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.MultiTimer = true
+			m := newTestModel(t, cfg)
+			m, _ = send(m, key(" "))
+			m, _ = send(m, tea.KeyMsg{Type: tea.KeyRight})
+			m, _ = send(m, key(" "))
+			other, viewed := m.Timers[0], m.Timers[1]
+			if other.Status != Running || viewed.Status != Running {
+				t.Fatalf("statuses %s and %s, want both running", other.Status, viewed.Status)
+			}
+
+			m, _ = send(m, tt.key)
+			got := m.Timers[1]
+			if got.Status != tt.want {
+				t.Errorf("viewed tab is %s, want %s", got.Status, tt.want)
+			}
+			if got.Status == Running && got.TickID == viewed.TickID {
+				t.Errorf("restarted tab kept tick loop %d", got.TickID)
+			}
+			if got := m.Timers[0]; got.Status != Running || got.TickID != other.TickID || !got.StartedAt.Equal(other.StartedAt) {
+				t.Errorf("other tab is %s with tick loop %d, want it running on %d as before", got.Status, got.TickID, other.TickID)
+			}
+		})
+	}
+}
@@ -0,0 +1,681 @@
+package pomodoro
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// borderSet is a BorderStyle's border along with the joints where the tabs
+// meet the window below them.
+type borderSet struct {
+	Border lipgloss.Border
+	// Window is the border of the window under the tabs.
+	Window lipgloss.Border
+	// Up joins two tab edges to the window's top, TeeLeft and TeeRight join
+	// the outer tabs to its sides and CornerLeft and CornerRight turn the
+	// window's top into the sides of the active tab.
+	Up, TeeLeft, TeeRight, CornerLeft, CornerRight string
+}
+
+var borderSets = map[BorderStyle]borderSet{
+	BorderRounded: {lipgloss.RoundedBorder(), lipgloss.NormalBorder(), "┴", "├", "┤", "└", "┘"},
+	BorderNormal:  {lipgloss.NormalBorder(), lipgloss.NormalBorder(), "┴", "├", "┤", "└", "┘"},
+	BorderThick:   {lipgloss.ThickBorder(), lipgloss.ThickBorder(), "┻", "┣", "┫", "┗", "┛"},
+	BorderDouble:  {lipgloss.DoubleBorder(), lipgloss.DoubleBorder(), "╩", "╠", "╣", "╚", "╝"},
+	BorderNone:    {lipgloss.HiddenBorder(), lipgloss.HiddenBorder(), " ", " ", " ", " ", " "},
+}
+
+// SetBorderStyle redraws the tab and window styles with the lines of style.
+func SetBorderStyle(style BorderStyle) {
+	tabLines = borderSets[style]
+	inactiveTabBorder = tabBorderWithBottom(tabLines.Up, tabLines.Border.Bottom, tabLines.Up)
+	activeTabBorder = tabBorderWithBottom(tabLines.CornerRight, " ", tabLines.CornerLeft)
+	inactiveTabStyle = inactiveTabStyle.Border(inactiveTabBorder, true)
+	activeTabStyle = inactiveTabStyle.Copy().Border(activeTabBorder, true)
+	windowStyle = windowStyle.Border(tabLines.Window).UnsetBorderTop()
+}
+
+func tabBorderWithBottom(left, middle, right string) lipgloss.Border {
+	border := tabLines.Border
+	border.BottomLeft = left
+	border.Bottom = middle
+	border.BottomRight = right
+	return border
+}
+
+// tabProgress returns the bar percentage and remaining time shown for a tab,
+// clamped so a last tick that overshoots still draws a full bar at zero.
+func (m Model) tabProgress(index int) (float64, time.Duration) {
+	t := m.Timers[index]
+	return max(min(t.Percent, 1), 0), max(m.getDurationByIndex(index)-t.CurrentTime, 0)
+}
+
+// verticalBarHeight is the number of rows a vertical bar fills.
+const verticalBarHeight = 10
+
+// verticalBar draws bar as a column two cells wide that fills upward to
+// percent, for layouts too narrow for the horizontal bar. It takes the fill
+// characters from bar, and its solid color rather than any gradient.
+func verticalBar(bar progress.Model, percent float64) string {
+	filled := int(math.Round(min(max(percent, 0), 1) * verticalBarHeight))
+	full := lipgloss.NewStyle().Foreground(lipgloss.Color(bar.FullColor)).Render(strings.Repeat(string(bar.Full), 2))
+	empty := lipgloss.NewStyle().Foreground(lipgloss.Color(bar.EmptyColor)).Render(strings.Repeat(string(bar.Empty), 2))
+
+	rows := make([]string, verticalBarHeight)
+	for i := range rows {
+		rows[i] = empty
+		if i >= verticalBarHeight-filled {
+			rows[i] = full
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+// bar returns the progress bar of a tab, drawn in a muted solid color while
+// its timer is paused.
+func (m Model) bar(index int) progress.Model {
+	bar := m.ProgressBars[index]
+	if m.Timers[index].Status == Paused {
+		progress.WithSolidFill(pausedBarColor)(&bar)
+	}
+	return bar
+}
+
+// barWidth is the width of a progress bar that fills a layout width columns
+// wide, leaving room for the margins, the window border and the remaining
+// time beside the bar.
+func barWidth(width int, showPercent bool) int {
+	bar := width - docStyle.GetHorizontalFrameSize() - windowStyle.GetHorizontalFrameSize() - 10
+	if showPercent {
+		bar -= 5
+	}
+	return max(bar, 10)
+}
+
+// standingBy reports whether the tab at index is idle while the session of
+// another tab is running or paused.
+func (m Model) standingBy(index int) bool {
+	return index != m.ProgressMode && m.Timers[index].Status == Idle && m.Timers[m.ProgressMode].Status != Idle
+}
+
+// barView draws the progress bar of a tab at percent, followed by the
+// percentage itself with ShowPercent.
+func (m Model) barView(index int, percent float64) string {
+	view := m.bar(index).ViewAs(percent)
+	if m.ShowPercent {
+		view += fmt.Sprintf(" %4s", fmt.Sprintf("%.0f%%", math.Floor(percent*100)))
+	}
+	return view
+}
+
+// formatRemaining writes the remaining time d of a session, rounded to
+// minutes as Rounding says while more than a minute is left. Within
+// fineCountdownFrom of the end, FineCountdown adds the tenths of a second.
+func (m Model) formatRemaining(d time.Duration) string {
+	if m.FineCountdown && d < fineCountdownFrom {
+		d = max(d, 0)
+		return fmt.Sprintf("%s.%d", formatDuration(d), d%time.Second/(100*time.Millisecond))
+	}
+	if d <= time.Minute {
+		return formatDuration(d)
+	}
+
+	switch m.Rounding {
+	case RoundNearest:
+		d = d.Round(time.Minute)
+	case RoundDown:
+		d = d.Truncate(time.Minute)
+	}
+	return formatDuration(d)
+}
+
+// formatDuration writes d as a clock, mm:ss, or h:mm:ss from an hour up,
+// dropping any fraction of a second. A negative d reads as 00:00.
+func formatDuration(d time.Duration) string {
+	seconds := int(max(d, 0) / time.Second)
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
+
+// formatSpan writes d for a sentence, to the minute like 1h10m, or to the
+// second like 30s under a minute.
+func formatSpan(d time.Duration) string {
+	if seconds := int(max(d, 0).Round(time.Second) / time.Second); seconds < 60 {
+		return fmt.Sprintf("%ds", seconds)
+	}
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	switch hours := minutes / 60; {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes%60 == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh%dm", hours, minutes%60)
+	}
+}
+
+func chosenView(m Model) string {
+	if m.Stats {
+		return statsView(m) + messagesView(m)
+	}
+	progressPercent, viewDuration := m.tabProgress(m.ActiveTab)
+	// The time shown small under the clock, if any.
+	var smallClock string
+	elapsed := m.Timers[m.ActiveTab].CurrentTime.Truncate(time.Second)
+	// The time next to the bar. Rounding and the fine countdown are for
+	// the time left, so elapsed time is written out as it is.
+	clock := m.formatRemaining(viewDuration)
+	switch m.Clock {
+	case ClockBoth:
+		smallClock = trf("%s in", formatDuration(elapsed))
+	case ClockElapsed:
+		smallClock = trf("%s left", clock)
+		viewDuration, clock = elapsed, formatDuration(elapsed)
+	}
+	msg := fmt.Sprintf("%s %s", m.barView(m.ActiveTab, progressPercent), clock)
+	if m.BigClock {
+		msg = m.barView(m.ActiveTab, progressPercent) + "\n\n" + bigClock(viewDuration)
+	}
+	if m.Vertical {
+		if m.BigClock {
+			clock = bigClock(viewDuration)
+		}
+		msg = lipgloss.JoinHorizontal(lipgloss.Center, verticalBar(m.bar(m.ActiveTab), progressPercent), "  ", clock)
+	}
+	if smallClock != "" {
+		msg += "\n" + hintStyle.Render(smallClock)
+	}
+	if t := m.Timers[m.ActiveTab]; t.Done && t.Status == Running {
+		msg += "\n" + toastStyle.Render(tr("Done!"))
+	}
+	if m.standingBy(m.ActiveTab) {
+		// A bar at 0% next to the full duration reads as a session about to
+		// run; this tab only waits for the one on another tab.
+		_, remaining := m.tabProgress(m.ActiveTab)
+		msg = hintStyle.Render(trf("%s ready", m.formatRemaining(remaining)))
+	}
+	if m.Stacked {
+		msg = stackedView(m)
+	}
+
+	if m.idle() && !m.FreeForm && !m.MultiTimer {
+		msg += "\n\n" + hintStyle.Render(m.nextHint())
+	}
+	if activity := m.activity(); activity != "" {
+		msg += "\n\n" + hintStyle.Render(trf("Break idea: %s", activity))
+	}
+
+	if d, ok := m.untilLongBreak(); m.LongBreakETA && !m.FreeForm && ok {
+		msg += "\n\n" + hintStyle.Render(trf("Long break in %s", formatSpan(d)))
+	}
+
+	if left := m.cooldown(); left > 0 {
+		msg += "\n\n" + hintStyle.Render(trf("Next session can start in %s", left))
+	}
+	if m.starting() {
+		msg += "\n\n" + toastStyle.Render(trf("%s starts in %d…", tr(m.Tabs[m.StartingMode].Name), m.countdown())) + "\n" + hintStyle.Render(tr("space to cancel"))
+	}
+
+	if m.standingBy(m.ActiveTab) {
+		current := m.Timers[m.ProgressMode].Status
+		format := "Viewing %s, %s is still running"
+		if current == Paused {
+			format = "Viewing %s, %s is still paused"
+		}
+		hint := trf(format, tr(m.Tabs[m.ActiveTab].Name), tr(m.Tabs[m.ProgressMode].Name))
+		msg += "\n\n" + hintStyle.Render(hint)
+	}
+
+	return msg + messagesView(m)
+}
+
+// messagesView is what goes under the view of any tab: notices, toasts and
+// prompts.
+func messagesView(m Model) string {
+	var msg string
+	if m.Notice != "" {
+		msg += "\n\n" + hintStyle.Render(m.Notice)
+	}
+
+	if m.Toast != "" {
+		msg += "\n\n" + toastStyle.Render(m.Toast)
+	}
+
+	if m.AskingReason {
+		msg += "\n\n" + tr("Why reset?") + " " + m.Reason.View() + "\n" + hintStyle.Render(tr("enter to save, esc to skip"))
+	}
+	if m.Task != "" {
+		msg += "\n\n" + hintStyle.Render(trf("Task: %s", m.taskView(m.Task)))
+	}
+	if m.Picking {
+		msg += "\n\n" + taskList(m) + "\n" + hintStyle.Render(tr("enter to pick, 0-9 to estimate, backspace to clear, esc to cancel"))
+	}
+	if m.Noting {
+		msg += "\n\n" + tr("Note:") + " " + m.Note.View() + "\n" + hintStyle.Render(tr("enter to save, esc to skip"))
+	}
+	if m.Jotting {
+		msg += "\n\n" + tr("Distraction:") + " " + m.Distraction.View() + "\n" + hintStyle.Render(tr("enter to save, esc to cancel"))
+	}
+
+	if m.Confirm != nil {
+		msg += "\n\n" + m.Confirm.Prompt
+	}
+
+	return msg
+}
+
+// activity returns the activity suggested for the break under way, if
+// there is one.
+func (m Model) activity() string {
+	if m.Timers[m.ProgressMode].Status == Idle || m.Tabs[m.ProgressMode].Kind == Work {
+		return ""
+	}
+	return m.Activity
+}
+
+// taskView shows task by its label with the pomodoros done on it, against
+// its estimate if it has one, e.g. "Refactor API (2/3)".
+func (m Model) taskView(task string) string {
+	label, done := todoLabel(task), m.TaskCounts[todoLabel(task)]
+	if estimate, ok := todoEstimate(task); ok {
+		return fmt.Sprintf("%s (%d/%d)", label, done, estimate)
+	}
+	if done > 0 {
+		return fmt.Sprintf("%s (%d)", label, done)
+	}
+	return label
+}
+
+// taskListRows is how many tasks the task list shows at a time.
+const taskListRows = 8
+
+// taskList renders the pending tasks around the highlighted one, a window of
+// taskListRows that scrolls with it.
+func taskList(m Model) string {
+	first := min(max(m.Pick-taskListRows/2, 0), max(len(m.Tasks)-taskListRows, 0))
+	var rows []string
+	for i := first; i < min(first+taskListRows, len(m.Tasks)); i++ {
+		row := "  " + m.taskView(m.Tasks[i])
+		if i == m.Pick {
+			row = lipgloss.NewStyle().Bold(true).Foreground(theme.Special).Render("> " + m.taskView(m.Tasks[i]))
+		}
+		rows = append(rows, row)
+	}
+	return lipgloss.NewStyle().Align(lipgloss.Left).Render(strings.Join(rows, "\n"))
+}
+
+// bareView renders only the active tab's bar and remaining time, for
+// embedding the timer in another layout. Prompts waiting for an answer are
+// still shown.
+func bareView(m Model) string {
+	progressPercent, viewDuration := m.tabProgress(m.ActiveTab)
+	view := fmt.Sprintf("%s %s", m.barView(m.ActiveTab, progressPercent), m.formatRemaining(viewDuration))
+	if m.Vertical {
+		view = verticalBar(m.bar(m.ActiveTab), progressPercent) + "\n" + m.formatRemaining(viewDuration)
+	}
+	if m.Confirm != nil {
+		view += "\n" + m.Confirm.Prompt
+	}
+	return view
+}
+
+// plainView describes the active tab in words, one line with no box
+// drawing or bars, e.g. "Pomodoro timer: 23 minutes 41 seconds remaining,
+// running", followed by any notice or prompt.
+func plainView(m Model) string {
+	_, viewDuration := m.tabProgress(m.ActiveTab)
+	status := tr("not started")
+	switch m.Timers[m.ActiveTab].Status {
+	case Running:
+		status = tr("running")
+	case Paused:
+		status = tr("paused")
+	}
+	lines := []string{trf("%s timer: %s remaining, %s", tr(m.Tabs[m.ActiveTab].Name), spokenDuration(viewDuration), status)}
+
+	current := m.Timers[m.ProgressMode].Status
+	if current != Idle && m.ActiveTab != m.ProgressMode {
+		format := "%s is running on another tab"
+		if current == Paused {
+			format = "%s is paused on another tab"
+		}
+		lines = append(lines, trf(format, tr(m.Tabs[m.ProgressMode].Name)))
+	}
+	if activity := m.activity(); activity != "" {
+		lines = append(lines, trf("Break idea: %s", activity))
+	}
+	if left := m.cooldown(); left > 0 {
+		lines = append(lines, trf("Next session can start in %s", left))
+	}
+	if m.starting() {
+		lines = append(lines, trf("%s starts in %d…", tr(m.Tabs[m.StartingMode].Name), m.countdown()))
+	}
+	for _, line := range []string{m.Notice, m.Toast} {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if m.AskingReason {
+		lines = append(lines, tr("Why reset?")+" "+m.Reason.Value())
+	}
+	if m.Jotting {
+		lines = append(lines, tr("Distraction:")+" "+m.Distraction.Value())
+	}
+	if m.Noting {
+		lines = append(lines, tr("Note:")+" "+m.Note.Value())
+	}
+	if m.Task != "" {
+		lines = append(lines, trf("Task: %s", m.taskView(m.Task)))
+	}
+	if m.Picking {
+		lines = append(lines, trf("Pick a task, %d of %d: %s", m.Pick+1, len(m.Tasks), m.taskView(m.Tasks[m.Pick])))
+	}
+	if m.EditingGoal {
+		lines = append(lines, goalView(m))
+	}
+	if m.Confirm != nil {
+		lines = append(lines, m.Confirm.Prompt)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// spokenDuration writes d out in words to the second, e.g. "23 minutes 41
+// seconds", for plainView.
+func spokenDuration(d time.Duration) string {
+	seconds := int(max(d, 0).Round(time.Second).Seconds())
+	var parts []string
+	for _, unit := range []struct {
+		size             int
+		singular, plural string
+	}{
+		{3600, "%d hour", "%d hours"},
+		{60, "%d minute", "%d minutes"},
+		{1, "%d second", "%d seconds"},
+	} {
+		n := seconds / unit.size
+		seconds %= unit.size
+		if n == 0 && (unit.size > 1 || len(parts) > 0) {
+			continue
+		}
+		format := unit.plural
+		if n == 1 {
+			format = unit.singular
+		}
+		parts = append(parts, trf(format, n))
+	}
+	return strings.Join(parts, " ")
+}
+
+// compactView renders the active tab on a single line for terminals too
+// narrow to fit the tab row and window borders. It drops the message, then
+// the tab's name, to fit in Width.
+func compactView(m Model) string {
+	_, viewDuration := m.tabProgress(m.ActiveTab)
+	name, remaining := tr(m.Tabs[m.ActiveTab].Name), m.formatRemaining(viewDuration)
+	lines := []string{
+		trf("%s %s - terminal too small", name, remaining),
+		name + " " + remaining,
+		remaining,
+	}
+	for _, line := range lines {
+		if m.Width <= 0 || lipgloss.Width(line) <= m.Width {
+			return line
+		}
+	}
+	return lipgloss.NewStyle().MaxWidth(m.Width).Render(remaining)
+}
+
+// stackedView renders every tab's bar in a column, marking the active tab and
+// coloring the one that is running.
+func stackedView(m Model) string {
+	nameWidth := 0
+	for _, t := range m.Tabs {
+		nameWidth = max(nameWidth, lipgloss.Width(tr(t.Name)))
+	}
+
+	// The names beside the bars take their room out of the bars.
+	m.ProgressBars = slices.Clone(m.ProgressBars)
+	for i := range m.ProgressBars {
+		m.ProgressBars[i].Width = max(m.ProgressBars[i].Width-nameWidth-3, 10)
+	}
+
+	var rows []string
+	for i, t := range m.Tabs {
+		progressPercent, viewDuration := m.tabProgress(i)
+
+		marker := "  "
+		nameStyle := lipgloss.NewStyle().Width(nameWidth)
+		if i == m.ActiveTab {
+			marker = "> "
+			nameStyle = nameStyle.Bold(true)
+		}
+		if m.Timers[i].Status == Running {
+			nameStyle = nameStyle.Foreground(theme.Special)
+		}
+
+		remaining := m.formatRemaining(viewDuration)
+		if m.standingBy(i) {
+			remaining = hintStyle.Render(trf("%s ready", remaining))
+		}
+		rows = append(rows, fmt.Sprintf("%s%s %s %s", marker, nameStyle.Render(tr(t.Name)), m.barView(i, progressPercent), remaining))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+// goalView shows the progress towards today's goal, or the input editing
+// it.
+func goalView(m Model) string {
+	if m.EditingGoal {
+		return trf("Today's goal: %s_ (enter to save, esc to cancel)", m.GoalInput)
+	}
+	if m.Goal <= 0 {
+		return ""
+	}
+
+	percent := min(float64(m.TodayPomodoros)/float64(m.Goal), 1)
+	nudge := tr("goal met 🎉")
+	if left := m.Goal - m.TodayPomodoros; left > 0 {
+		nudge = trf("%d more to hit today's goal", left)
+	}
+	if m.GoalDays > 0 {
+		nudge += ", " + trf("met %d of the last %d days", m.GoalDaysMet, m.GoalDays)
+	}
+	return hintStyle.Render(trf("Goal: %d/%d", m.TodayPomodoros, m.Goal)) + " " + m.GoalBar.ViewAs(percent) + " " + hintStyle.Render(nudge)
+}
+
+func (m Model) View() string {
+	if m.Transcript {
+		return ""
+	}
+	if m.Bare {
+		return m.clip(bareView(m))
+	}
+	if m.Plain {
+		return plainView(m)
+	}
+
+	doc := strings.Builder{}
+
+	// The tabs close up before the layout gives way to compactView.
+	padding := 5
+	row := tabRow(m, false, padding)
+	if m.FixedWidth <= 0 && m.Width > 0 && m.Width < lipgloss.Width(row)+docStyle.GetHorizontalFrameSize() {
+		padding = 1
+		row = tabRow(m, false, padding)
+	}
+	width := lipgloss.Width(row)
+	if m.FixedWidth > 0 {
+		width = m.FixedWidth - docStyle.GetHorizontalFrameSize()
+	} else if m.Width > 0 && m.Width < width+docStyle.GetHorizontalFrameSize() {
+		return compactView(m)
+	} else if m.Width > 0 {
+		width = m.Width - docStyle.GetHorizontalFrameSize()
+	}
+	count := ""
+	if m.CompletedPomodoros > 0 {
+		count = fmt.Sprintf("🍅 × %d", m.CompletedPomodoros)
+	}
+	if gap := width - lipgloss.Width(row); gap > 0 {
+		// Carry the top of the window on past the tabs, with the count of
+		// pomodoros above it if there is room.
+		top := lipgloss.NewStyle().Foreground(theme.Border).Render(strings.Repeat(tabLines.Window.Top, gap-1) + tabLines.Window.TopRight)
+		if count != "" && lipgloss.Width(count)+1 < gap {
+			top = lipgloss.JoinVertical(lipgloss.Right, count+" ", "", top)
+			count = ""
+		}
+		row = lipgloss.JoinHorizontal(lipgloss.Bottom, tabRow(m, true, padding), top)
+	}
+
+	doc.WriteString(row)
+	doc.WriteString("\n")
+	doc.WriteString(windowStyle.Width((width - windowStyle.GetHorizontalFrameSize())).Render(chosenView(m)))
+	doc.WriteString("\n")
+	doc.WriteString(hintStyle.Render(trf("Focus score: %d", m.focusScore())))
+	if count != "" {
+		doc.WriteString(hintStyle.Render(" · " + count))
+	}
+	if m.Strict {
+		doc.WriteString(hintStyle.Render(" · " + tr("strict")))
+	}
+	if view := muteView(m); view != "" {
+		doc.WriteString("\n")
+		doc.WriteString(hintStyle.Render(view))
+	}
+	if m.ShowLifetime {
+		doc.WriteString("\n")
+		doc.WriteString(hintStyle.Render(trf("Lifetime: %s pomodoros", groupDigits(m.Lifetime))))
+	}
+	if view := goalView(m); view != "" {
+		doc.WriteString("\n")
+		doc.WriteString(view)
+	}
+	if m.KeysFooter {
+		doc.WriteString("\n\n")
+		doc.WriteString(keysStyle.Render(keysFooter(m)))
+	}
+	return m.clip(docStyle.Render(doc.String()))
+}
+
+// muteView says what m and M have muted, if anything.
+func muteView(m Model) string {
+	switch {
+	case !m.Notifications && !m.Sounds:
+		return tr("Notifications and sounds muted")
+	case !m.Notifications:
+		return tr("Notifications muted")
+	case !m.Sounds:
+		return tr("Sounds muted")
+	}
+	return ""
+}
+
+// keysFooter lists the most common keys under the window, naming what space
+// does on the viewed tab and leaving switching out while LockTabs holds it.
+func keysFooter(m Model) string {
+	status := m.Timers[m.ActiveTab].Status
+	if !m.MultiTimer && m.Timers[m.ProgressMode].Status == Paused {
+		// Space resumes a paused session from any tab.
+		status = Paused
+	}
+	space := tr("space start")
+	if m.starting() {
+		space = tr("space cancel")
+	}
+	switch status {
+	case Running:
+		space = tr("space pause")
+	case Paused:
+		space = tr("space resume")
+	}
+
+	keys := []string{space, tr("r reset")}
+	if m.Stats && !m.starting() {
+		// Space does nothing on the stats tab.
+		keys = keys[1:]
+	}
+	if !m.LockTabs || m.idle() {
+		keys = append(keys, tr("←/→ switch"))
+	}
+	keys = append(keys, tr("? hide"), tr("q quit"))
+	return strings.Join(keys, " · ")
+}
+
+// tabRow renders the tabs side by side, their bottoms joining the window
+// below, with padding columns either side of each name. With open, the
+// window's top carries on past the last tab.
+func tabRow(m Model, open bool, padding int) string {
+	var renderedTabs []string
+
+	names := make([]string, 0, len(m.Tabs)+1)
+	for _, t := range m.Tabs {
+		names = append(names, t.Name)
+	}
+	names = append(names, "Stats")
+	active := m.ActiveTab
+	if m.Stats {
+		active = len(m.Tabs)
+	}
+
+	for i, name := range names {
+		var style lipgloss.Style
+		isFirst, isLast, isActive := i == 0, i == len(names)-1, i == active
+
+		if isActive {
+			style = activeTabStyle.Copy()
+		} else {
+			style = inactiveTabStyle.Copy()
+		}
+
+		border, _, _, _, _ := style.GetBorder()
+
+		if isFirst && isActive {
+			border.BottomLeft = tabLines.Window.Left
+		} else if isFirst && !isActive {
+			border.BottomLeft = tabLines.TeeLeft
+		} else if isLast && isActive && !open {
+			border.BottomRight = tabLines.Window.Right
+		} else if isLast && !isActive && !open {
+			border.BottomRight = tabLines.TeeRight
+		}
+
+		style = style.Border(border).Padding(0, padding)
+		if i < len(m.Timers) && m.Timers[i].Status == Running {
+			style = style.Bold(true).Foreground(theme.Special)
+		}
+
+		renderedTabs = append(renderedTabs, style.Render(tr(name)))
+
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, renderedTabs...)
+}
+
+// clip cuts every line of view to the forced layout width, if there is one.
+func (m Model) clip(view string) string {
+	if m.FixedWidth <= 0 {
+		return view
+	}
+	return lipgloss.NewStyle().MaxWidth(m.FixedWidth).Render(view)
+}
+
+// OnceSummary says how the -once session went, once the app quit.
+func (m Model) OnceSummary() string {
+	name := tr(m.Tabs[m.StartMode].Name)
+	if m.Completed {
+		return trf("%s completed", name)
+	}
+	if m.Timers[m.StartMode].Status == Idle {
+		return trf("%s reset", name)
+	}
+	_, remaining := m.tabProgress(m.StartMode)
+	return trf("%s stopped with %s left", name, remaining)
+}
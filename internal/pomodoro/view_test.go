@@ -0,0 +1,215 @@
+package pomodoro
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/muesli/termenv"
+)
+
+func TestMain(m *testing.M) {
+	// Render the styles as a color terminal would, so the goldens catch a
+	// style that gets lost, such as the bold of the active tab.
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	lipgloss.SetHasDarkBackground(true)
+	os.Exit(m.Run())
+}
+
+// goldenModel runs m in a test program sized 80x24, sends it msgs and
+// compares the view it ends up with against the golden file of the test.
+// Run the tests with -update to write the golden files anew.
+func goldenModel(t *testing.T, m Model, wait string, msgs ...tea.Msg) {
+	t.Helper()
+	tm := teatest.NewTestModel(t, m, teatest.WithInitialTermSize(80, 24))
+	for _, msg := range msgs {
+		tm.Send(msg)
+	}
+	if wait != "" {
+		teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+			return bytes.Contains(out, []byte(wait))
+		}, teatest.WithDuration(5*time.Second))
+	}
+	if err := tm.Quit(); err != nil {
+		t.Fatal(err)
+	}
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(Model)
+	teatest.RequireEqualOutput(t, []byte(final.View()))
+}
+
+func TestViewGolden(t *testing.T) {
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+	shorten := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-")}
+	states := []struct {
+		name string
+		keys []tea.Msg
+		wait string
+	}{
+		{"idle", nil, ""},
+		{"running", []tea.Msg{space}, ""},
+		{"paused", []tea.Msg{space, space}, ""},
+		// Shortened to no more than it has run, the session completes.
+		{"completed", []tea.Msg{space, shorten}, "complete!"},
+	}
+
+	for tab, session := range defaultConfig().Sessions {
+		for _, state := range states {
+			name := strings.ReplaceAll(strings.ToLower(session.Name), " ", "_") + "_" + state.name
+			t.Run(name, func(t *testing.T) {
+				cfg := defaultConfig()
+				// Each tab starts its own session, rather than the next of
+				// the cycle, and the breaks keep their own durations.
+				cfg.FreeForm = true
+				cfg.ShortBreakRatio, cfg.LongBreakRatio = 0, 0
+				cfg.DoneHold = 0
+				if state.name == "completed" {
+					cfg.Sessions[tab].Duration = adjustStep
+				}
+
+				var msgs []tea.Msg
+				for i := 0; i < tab; i++ {
+					msgs = append(msgs, tea.KeyMsg{Type: tea.KeyRight})
+				}
+				isolate(t)
+				m := NewModel(cfg, &fakeNotifier{})
+				goldenModel(t, m, state.wait, append(msgs, state.keys...)...)
+			})
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "00:00"},
+		{42 * time.Second, "00:42"},
+		{59*time.Second + 900*time.Millisecond, "00:59"},
+		{25 * time.Minute, "25:00"},
+		{59*time.Minute + 59*time.Second, "59:59"},
+		{time.Hour, "1:00:00"},
+		{90 * time.Minute, "1:30:00"},
+		{3 * time.Hour, "3:00:00"},
+		{12*time.Hour + 5*time.Second, "12:00:05"},
+		// There is no overrun to show; remaining time stops at zero.
+		{-5 * time.Second, "00:00"},
+	}
+	for _, tt := range tests {
+		if got := formatDuration(tt.d); got != tt.want {
+			t.Errorf("formatDuration(%s) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestFormatSpan(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{10 * time.Second, "10s"},
+		{30 * time.Second, "30s"},
+		{42318 * time.Millisecond, "42s"},
+		{90 * time.Second, "2m"},
+		{25 * time.Minute, "25m"},
+		{time.Hour, "1h"},
+		{70*time.Minute + 20*time.Second, "1h10m"},
+	}
+	for _, tt := range tests {
+		if got := formatSpan(tt.d); got != tt.want {
+			t.Errorf("formatSpan(%s) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestPausedBarDiffers(t *testing.T) {
+	m := newTestModel(t, defaultConfig())
+	mode := m.workIndex()
+	// The bars pick their color profile up from stdout, which in a test
+	// has none.
+	progress.WithColorProfile(termenv.ANSI256)(&m.ProgressBars[mode])
+	m, _ = send(m, key(" "))
+	running := m.barView(mode, 0.4)
+	m, _ = send(m, key(" "))
+	paused := m.barView(mode, 0.4)
+
+	if paused == running {
+		t.Fatalf("the paused bar renders as the running one: %q", paused)
+	}
+	if gray := termenv.ANSI256.Color(pausedBarColor).Sequence(false); !strings.Contains(paused, gray) {
+		t.Errorf("paused bar %q is not in %s", paused, pausedBarColor)
+	}
+	if lipgloss.Width(paused) != lipgloss.Width(running) {
+		t.Errorf("paused bar is %d wide, running %d", lipgloss.Width(paused), lipgloss.Width(running))
+	}
+}
+
+func TestTabProgressClamps(t *testing.T) {
+	const d = 25 * time.Minute
+	tests := []struct {
+		name          string
+		percent       float64
+		current       time.Duration
+		wantPercent   float64
+		wantRemaining time.Duration
+	}{
+		{"just below full", 1 - 1e-9, d - time.Millisecond, 1 - 1e-9, time.Millisecond},
+		{"just above full", 1 + 1e-9, d, 1, 0},
+		{"overrun", 1.2, d + 5*time.Minute, 1, 0},
+		{"just above zero", 1e-9, 0, 1e-9, d},
+		{"just below zero", -1e-9, 0, 0, d},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t, defaultConfig())
+			mode := m.workIndex()
+			m.Timers[mode] = timer{Status: Running, Percent: tt.percent, CurrentTime: tt.current}
+
+			percent, remaining := m.tabProgress(mode)
+			if percent != tt.wantPercent || remaining != tt.wantRemaining {
+				t.Errorf("tabProgress = %v, %s, want %v, %s", percent, remaining, tt.wantPercent, tt.wantRemaining)
+			}
+		})
+	}
+}
+
+func TestElapsedClockIgnoresRounding(t *testing.T) {
+	tests := []struct {
+		name       string
+		current    time.Duration
+		wantClock  string // elapsed, next to the bar
+		wantLeft   string // remaining, below it
+		notInClock string
+	}{
+		{"rounded", 10*time.Minute + 20*time.Second, " 10:20\n", "15:00 left", "10:00"},
+		{"fine countdown", 25*time.Minute - 5*time.Second, " 24:55\n", "00:05.0 left", "24:55."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.Clock = ClockElapsed
+			cfg.Rounding = RoundNearest
+			cfg.FineCountdown = true
+			m := newTestModel(t, cfg)
+			mode := m.workIndex()
+			m.startTimer(mode)
+			m.Timers[mode].CurrentTime = tt.current
+			m.Timers[mode].Percent = tt.current.Seconds() / (25 * time.Minute).Seconds()
+
+			view := chosenView(m)
+			if !strings.Contains(view, tt.wantClock) || strings.Contains(view, tt.notInClock) {
+				t.Errorf("view %q does not show the elapsed %q as it is", view, strings.TrimSpace(tt.wantClock))
+			}
+			if !strings.Contains(view, tt.wantLeft) {
+				t.Errorf("view %q does not show %q", view, tt.wantLeft)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package pomodoro
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// dataHome returns $XDG_DATA_HOME, defaulting to ~/.local/share.
+func dataHome() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share"), nil
+}
+
+// configHome returns $XDG_CONFIG_HOME, defaulting to ~/.config.
+func configHome() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config"), nil
+}
+
+// cacheHome returns $XDG_CACHE_HOME, defaulting to ~/.cache.
+func cacheHome() (string, error) {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".cache"), nil
+}
+
+// dataDirs returns the directories searched for data files in order of
+// preference: $XDG_DATA_HOME followed by $XDG_DATA_DIRS.
+func dataDirs() []string {
+	var dirs []string
+	if dir, err := dataHome(); err == nil {
+		dirs = append(dirs, dir)
+	}
+
+	system := os.Getenv("XDG_DATA_DIRS")
+	if system == "" {
+		system = "/usr/local/share:/usr/share"
+	}
+	for _, dir := range strings.Split(system, ":") {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	return dirs
+}
+
+// findDataFile looks up name under the pomodoro directory of each XDG data
+// dir and returns the first existing path, or "" if there is none.
+func findDataFile(name string) string {
+	for _, dir := range dataDirs() {
+		path := filepath.Join(dir, "pomodoro", name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
@@ -1,235 +1,242 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
-	"github.com/charmbracelet/bubbles/progress"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
-	"github.com/gen2brain/beeep"
-)
-
-type ProgressStatus string
-
-const (
-	Idle    ProgressStatus = "idle"
-	Paused  ProgressStatus = "paused"
-	Running ProgressStatus = "running"
-)
+	"golang.org/x/term"
 
-var (
-	inactiveTabBorder = tabBorderWithBottom("┴", "─", "┴")
-	activeTabBorder   = tabBorderWithBottom("┘", " ", "└")
-	docStyle          = lipgloss.NewStyle().Padding(1, 2, 1, 2)
-	highlightColor    = lipgloss.AdaptiveColor{Light: "#874BFD", Dark: "#7D56F4"}
-	specialColor      = lipgloss.AdaptiveColor{Light: "#43BF6D", Dark: "#73F59F"}
-	inactiveTabStyle  = lipgloss.NewStyle().Border(inactiveTabBorder, true).BorderForeground(highlightColor).Padding(0, 1)
-	activeTabStyle    = inactiveTabStyle.Copy().Border(activeTabBorder, true)
-	windowStyle       = lipgloss.NewStyle().BorderForeground(highlightColor).Padding(2, 0).Align(lipgloss.Center).Border(lipgloss.NormalBorder()).UnsetBorderTop()
+	"github.com/bartosz121/pomodoro/internal/pomodoro"
 )
 
-type model struct {
-	Tabs                     []string
-	ActiveTab                int
-	ProgressMode             int
-	ProgressPomodoro         progress.Model
-	ProgressShort            progress.Model
-	ProgressLong             progress.Model
-	ProgressStatus           ProgressStatus
-	ProgressPomodoroDuration time.Duration
-	ProgressShortDuration    time.Duration
-	ProgressLongDuration     time.Duration
-	ProgressCurrentTime      time.Duration
-	ProgressPercent          float64
-}
-
-type tickMsg struct{}
-type progressDoneMsg struct{}
-
-func initialModel() model {
-	return model{
-		Tabs:                     []string{"Pomodoro", "Short break", "Long break"},
-		ActiveTab:                0, // Tabs index
-		ProgressMode:             0, // Tabs index
-		ProgressPomodoro:         progress.New(progress.WithDefaultGradient(), progress.WithoutPercentage()),
-		ProgressShort:            progress.New(progress.WithDefaultGradient(), progress.WithoutPercentage()),
-		ProgressLong:             progress.New(progress.WithDefaultGradient(), progress.WithoutPercentage()),
-		ProgressStatus:           Idle,
-		ProgressPomodoroDuration: 5 * time.Second,
-		ProgressShortDuration:    120 * time.Second,
-		ProgressLongDuration:     180 * time.Second,
-		ProgressCurrentTime:      0,
-		ProgressPercent:          0.0,
-	}
-}
-
-func (m *model) resetProgress() {
-	m.ProgressCurrentTime = 0
-	m.ProgressPercent = 0.0
-	m.ProgressStatus = Idle
-}
-
-func (m model) getDurationByIndex(index int) time.Duration {
-	switch index {
-	case 0:
-		return m.ProgressPomodoroDuration
-	case 1:
-		return m.ProgressShortDuration
-	case 2:
-		return m.ProgressLongDuration
-	default:
-		panic("")
+func main() {
+	cfg, err := pomodoro.LoadConfig()
+	if err != nil {
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", line)
+		}
 	}
-}
-
-func tick() tea.Cmd {
-	return tea.Tick(time.Second, func(time.Time) tea.Msg {
-		return tickMsg{}
-	})
-}
-
-func progressDone() tea.Cmd {
-	return tea.Tick(time.Second, func(time.Time) tea.Msg {
-		beeep.Alert("Pomodoro done", "", "assets/pomodoro.png")
-		return progressDoneMsg{}
+	schedule := flag.Int("schedule", 0, "print the next `n` sessions of the cycle with their end times and exit")
+	simulate := flag.Duration("simulate", 0, "print the sessions of the cycle that fit in this long a day, e.g. 8h, with the totals and exit")
+	importFile := flag.String("import", "", "merge the session records in a CSV or JSON `file` into the history log and exit")
+	exportFile := flag.String("export-csv", "", "write the history log to a CSV `file`, one row per session, and exit")
+	exportWeekly := flag.Bool("export-weekly", false, "with -export-csv, write the totals of each week instead of the sessions")
+	exportMonthly := flag.Bool("export-monthly", false, "with -export-csv, write the totals of each month instead of the sessions")
+	heatmap := flag.Bool("heatmap", false, "print a calendar of the pomodoros completed each day over the last year and exit")
+	review := flag.Bool("review", false, "print yesterday's sessions, with the focus time, interruptions and notes, and exit")
+	daemon := flag.Bool("daemon", false, "run without a UI, only sending notifications (SIGUSR1 start/pause, SIGUSR2 reset)")
+	flag.Var(&cfg.Once, "once", "like -start, but also takes a duration for a work session of that length, e.g. 25m, and prints how it went")
+	flag.Var(&cfg.Start, "start", "run one session of this kind - work, short or long - and quit when it completes; exits 0 if it did and 3 if it was abandoned")
+	setDurations := cfg.DurationFlags(flag.CommandLine)
+	flag.BoolVar(&cfg.AutoStart, "go", cfg.AutoStart, "start a pomodoro on launch and carry on with the cycle; named -go because -start runs a single session and quits")
+	flag.BoolVar(&cfg.FreeForm, "free", cfg.FreeForm, "start the active tab on space instead of following the pomodoro cycle")
+	flag.IntVar(&cfg.LongBreakInterval, "long-break-interval", cfg.LongBreakInterval, "number of pomodoros before a long break")
+	flag.BoolVar(&cfg.Bell, "bell", cfg.Bell, "ring the terminal bell when a session completes")
+	flag.BoolVar(&cfg.MultiTimer, "multi", cfg.MultiTimer, "let every tab run its own timer at the same time")
+	flag.BoolVar(&cfg.RatioBreaks, "ratio-breaks", cfg.RatioBreaks, "size the breaks by the work duration, see -short-break-ratio and -long-break-ratio")
+	flag.Float64Var(&cfg.ShortBreakRatio, "short-break-ratio", cfg.ShortBreakRatio, "with -ratio-breaks, the short break is the work duration divided by this")
+	flag.Float64Var(&cfg.LongBreakRatio, "long-break-ratio", cfg.LongBreakRatio, "with -ratio-breaks, the long break is the work duration divided by this")
+	flag.BoolVar(&cfg.ConfirmOverwrite, "confirm-overwrite", cfg.ConfirmOverwrite, "ask before starting a tab discards the running session")
+	flag.Var(&cfg.OtherTab, "other-tab", "what space does on a tab other than the running session: restart, switch or ignore; a paused session resumes from any tab")
+	flag.BoolVar(&cfg.ConfirmQuit, "confirm-quit", cfg.ConfirmQuit, "ask before q or esc quits while a pomodoro is running")
+	flag.BoolVar(&cfg.Strict, "strict", cfg.Strict, "keep a pomodoro from being paused, reset or skipped once it starts; only quitting stops it")
+	flag.BoolVar(&cfg.LockTabs, "lock-tabs", cfg.LockTabs, "disable switching tabs while a session is running or paused")
+	flag.DurationVar(&cfg.IdleQuit, "idle-quit", cfg.IdleQuit, "quit after sitting idle this long, e.g. 30m (0 disables)")
+	lang := flag.String("lang", pomodoro.DetectLanguage(), "language of the UI and notifications, e.g. en, pl, de")
+	flag.StringVar(&cfg.WorkIcon, "work-icon", cfg.WorkIcon, "notification icon for completed pomodoros")
+	flag.StringVar(&cfg.BreakIcon, "break-icon", cfg.BreakIcon, "notification icon for completed breaks")
+	flag.Float64Var(&cfg.ScorePomodoroWeight, "score-pomodoro-weight", cfg.ScorePomodoroWeight, "focus score points per completed pomodoro")
+	flag.Float64Var(&cfg.ScoreMinuteWeight, "score-minute-weight", cfg.ScoreMinuteWeight, "focus score points per minute of focus")
+	flag.Var(&cfg.SleepPolicy, "on-sleep", "what to do with a session the system slept through: credit or pause")
+	flag.Var(&cfg.BarFull, "bar-full", "character for the filled part of the progress bar")
+	flag.Var(&cfg.BarEmpty, "bar-empty", "character for the empty part of the progress bar")
+	flag.DurationVar(&cfg.PreBreakLead, "pre-break", cfg.PreBreakLead, "send a heads-up this long before a pomodoro ends, e.g. 30s (0 disables)")
+	flag.Var(&cfg.Notify, "notify", "notification backend: beeep, notify-send, osascript or none")
+	flag.BoolVar(&cfg.SetTitle, "title", cfg.SetTitle, "show the running session in the terminal title")
+	flag.BoolVar(&cfg.DailySummary, "daily-summary", cfg.DailySummary, "send a notification with the day's totals when the day rolls over")
+	flag.BoolVar(&cfg.FineCountdown, "fine-countdown", cfg.FineCountdown, "count the last ten seconds of a session down in tenths of a second")
+	flag.StringVar(&cfg.Profile, "profile", cfg.Profile, "tag logged sessions with this profile `name`, e.g. coding or writing")
+	flag.BoolVar(&cfg.ProfileStats, "profile-stats", cfg.ProfileStats, "count only sessions of the current -profile in today's totals and focus score")
+	flag.BoolVar(&cfg.BigClock, "big", cfg.BigClock, "show the remaining time in large block digits")
+	flag.BoolVar(&cfg.Transcript, "transcript", cfg.Transcript, "run inline without redrawing a UI, printing a line for every change of the timers; keys still control them (on by default when stdout is not a terminal)")
+	flag.BoolVar(&cfg.ShowLifetime, "lifetime", cfg.ShowLifetime, "show the number of pomodoros ever completed")
+	flag.BoolVar(&cfg.CleanOnly, "clean-only", cfg.CleanOnly, "count only pomodoros paused for no longer than -max-pause in today's totals and focus score")
+	flag.DurationVar(&cfg.MaxPause, "max-pause", cfg.MaxPause, "with -clean-only, the pause time a pomodoro may have and still count, e.g. 2m")
+	flag.IntVar(&cfg.Goal, "goal", cfg.Goal, "daily goal in pomodoros, can be changed for the day with g (0 for none)")
+	flag.DurationVar(&cfg.Repaint, "repaint", cfg.Repaint, "redraw the UI this often so it stays fresh while no session ticks (0 disables)")
+	flag.Var(&cfg.Rounding, "round", "show the remaining time to the second, or rounded to the nearest or down to whole minutes until the last one: seconds, nearest or down")
+	flag.BoolVar(&cfg.AskReason, "reset-reason", cfg.AskReason, "ask why when a running session is reset and log it with the abandoned session")
+	flag.BoolVar(&cfg.Tones, "tones", cfg.Tones, "beep a rising tone when a session starts and a falling one when it completes")
+	flag.BoolVar(&cfg.Bare, "bare", cfg.Bare, "render only the progress bar and remaining time, without tabs or borders")
+	flag.DurationVar(&cfg.StartDelay, "start-delay", cfg.StartDelay, "count down this long after space before a pomodoro starts, e.g. 3s")
+	flag.DurationVar(&cfg.Cooldown, "cooldown", cfg.Cooldown, "wait this long after a session completes before another can start, e.g. 3s")
+	flag.BoolVar(&cfg.FocusWork, "focus-work", cfg.FocusWork, "always open on the work tab, whatever tab would be shown otherwise")
+	flag.IntVar(&cfg.Width, "width", cfg.Width, "lay the app out to this many columns whatever the terminal size, e.g. 60 for screenshots")
+	flag.BoolVar(&cfg.Plain, "plain", cfg.Plain, "describe the timer in a line of plain text instead of drawing it, for screen readers")
+	flag.BoolVar(&cfg.PauseOnBlur, "pause-on-blur", cfg.PauseOnBlur, "pause the running session while the terminal is out of focus, where the terminal reports it")
+	flag.Var(&cfg.Hooks, "on", "run a shell command on an event, as event=command; repeat for more events (see the README)")
+	flag.BoolVar(&cfg.Vertical, "vertical", cfg.Vertical, "draw the progress bar as a column that fills upward, for narrow layouts")
+	flag.StringVar(&cfg.Theme, "theme", cfg.Theme, "color scheme: purple, forest or mono")
+	flag.Var(&cfg.Border, "border", "style of the lines around the tabs and the window: rounded, normal, thick, double or none")
+	flag.BoolVar(&cfg.KeysFooter, "keys", cfg.KeysFooter, "show the most common keys under the window; ? toggles it")
+	flag.Bool("precise", true, "deprecated and ignored, the timer always follows the wall clock")
+	flag.BoolVar(&cfg.AskNote, "notes", cfg.AskNote, "ask for a note on each completed pomodoro and log it with the session")
+	flag.BoolVar(&pomodoro.HistoryBackups, "backup", pomodoro.HistoryBackups, "back the history log up once a day before writing to it, and offer to restore it if it gets damaged")
+	flag.Var(&pomodoro.DayStartHour, "day-start", "hour the day rolls over at for the daily totals, goals and calendar, e.g. 4 to count sessions until 4am to the day before")
+	flag.BoolVar(&cfg.LongBreakETA, "long-break-eta", cfg.LongBreakETA, "show how long it is until the next long break")
+	flag.StringVar(&cfg.DoneTitle, "done-title", cfg.DoneTitle, "title of the notification when a session completes, a template over .Completed, .Goal, .Type, .Name and .Duration")
+	flag.StringVar(&cfg.DoneMessage, "done-message", cfg.DoneMessage, "body of the notification when a session completes, a template like -done-title, e.g. '{{.Completed}} today'")
+	flag.Var(&cfg.QuietHours, "quiet-hours", "keep notifications and sounds quiet in this window of the day, e.g. 22:00-08:00; completions still show in the app")
+	flag.StringVar(&cfg.TodoFile, "todo", cfg.TodoFile, "todo.txt `file` to pick the task of a session from with t")
+	flag.Var(&cfg.TodoAction, "todo-done", "what a completed pomodoro does to its task: none, count (in a pomo: tag) or done")
+	flag.DurationVar(&cfg.MaxSession, "max-session", cfg.MaxSession, "stop a session that has run this long without completing, e.g. 4h (0 disables)")
+	flag.BoolVar(&cfg.ShowPercent, "percent", cfg.ShowPercent, "show the percentage of the session done next to the progress bar")
+	flag.StringVar(&cfg.WatchProcess, "watch", cfg.WatchProcess, "pause a running pomodoro while no process of this `name` is running, and resume it when one is, e.g. code")
+	flag.DurationVar(&cfg.SnoozeFor, "snooze", cfg.SnoozeFor, "how long z puts off the completion notification for, to send it again (0 disables)")
+	flag.IntVar(&cfg.MaxSnoozes, "snoozes", cfg.MaxSnoozes, "times a completion can be snoozed with z")
+	flag.Float64Var(&cfg.MinBreak, "min-break", cfg.MinBreak, "fraction of the break after a pomodoro that has to pass before the next can start, e.g. 0.5 (0 disables)")
+	flag.Var(&cfg.Activities, "break-activity", "suggest this on a break, e.g. 'get some water'; repeat for more and one is picked at random")
+	flag.Var(&cfg.Clock, "clock", "time shown next to the bar: remaining, both (the elapsed time below it) or elapsed (the remaining time below it)")
+	flag.Var(&cfg.TabOrder, "tabs", "tabs to show and their order, e.g. work,short to leave the long break out")
+	flag.BoolVar(&cfg.Events, "events", cfg.Events, "run as -daemon, writing every start, pause, resume, reset and completion to stdout as a line of JSON (see the README)")
+	flag.BoolVar(&cfg.EventTicks, "event-ticks", cfg.EventTicks, "with -events, also write a tick event every tick of the running session")
+	flag.DurationVar(&cfg.DoneHold, "done-hold", cfg.DoneHold, "how long a completed session shows its full bar before the next one is set up, e.g. 2s")
+	flag.BoolVar(&cfg.GoalStop, "goal-stop", cfg.GoalStop, "stop starting the next session by itself once today's -goal is met")
+	flag.BoolVar(&cfg.AutoAdvance, "auto", cfg.AutoAdvance, "start the next session of the cycle as soon as one completes; A toggles it")
+	flag.Var(&cfg.ConfirmSkip, "confirm-skip", "which sessions s asks about before skipping them: none, work or all")
+	flag.BoolVar(&cfg.VerboseLog, "verbose-log", cfg.VerboseLog, "record pause and resume times in the history log")
+	flag.Var(&cfg.LogTo, "log-to", "where to log sessions: file (the history log), syslog or both")
+	flag.Parse()
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "precise" {
+			fmt.Fprintln(os.Stderr, "warning: -precise is deprecated and ignored, the timer always follows the wall clock")
+		}
 	})
-}
-
-func (m model) Init() tea.Cmd {
-	return nil
-}
-
-func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch keypress := msg.String(); keypress {
-		case "ctrl+c", "q":
-			return m, tea.Quit
-		case "r":
-			m.resetProgress()
-			return m, nil
-		case "right", "d", "tab":
-			m.ActiveTab = min(m.ActiveTab+1, len(m.Tabs)-1)
-			return m, nil
-		case "left", "a":
-			m.ActiveTab = max(m.ActiveTab-1, 0)
-			return m, nil
-		case " ":
-			if m.ProgressStatus == Idle {
-				m.ProgressMode = m.ActiveTab
-				m.ProgressStatus = Running
-				return m, tick()
-			}
-
-			if m.ProgressMode == m.ActiveTab {
-				if m.ProgressStatus == Running {
-					m.ProgressStatus = Paused
-					return m, tick()
-				}
-
-				if m.ProgressStatus == Paused {
-					m.ProgressStatus = Running
-					return m, tick()
-				}
-			}
+	pomodoro.SetLanguage(*lang)
+	if err := setDurations(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	cfg.ApplyOnce()
+	cfg.ApplyTabs()
+	if err := pomodoro.SetTheme(cfg.Theme); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+	pomodoro.SetBorderStyle(cfg.Border)
 
-			return m, nil
+	notifier, err := pomodoro.NewNotifier(cfg.Notify)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+	if err := pomodoro.SetLogTarget(cfg.LogTo); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
 
+	if *schedule > 0 {
+		if err := pomodoro.PrintSchedule(os.Stdout, pomodoro.NewModel(cfg, notifier), *schedule, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "schedule: %v\n", err)
+			os.Exit(1)
 		}
-	case tickMsg:
-		if m.ProgressPercent >= 1.0 {
-			m.ProgressPercent = 1.0
-			return m, progressDone()
-		}
+		return
+	}
 
-		if m.ProgressStatus == Running {
-			m.ProgressCurrentTime += 1 * time.Second
-			m.ProgressPercent += 1.0 / float64(m.getDurationByIndex(m.ProgressMode).Seconds())
-			return m, tick()
+	if *simulate > 0 {
+		if err := pomodoro.PrintSimulation(os.Stdout, pomodoro.NewModel(cfg, notifier), *simulate, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "simulate: %v\n", err)
+			os.Exit(1)
 		}
-
-		return m, nil
-
-	case progressDoneMsg:
-		m.resetProgress()
-		return m, nil
+		return
 	}
 
-	return m, nil
-}
-
-func tabBorderWithBottom(left, middle, right string) lipgloss.Border {
-	border := lipgloss.RoundedBorder()
-	border.BottomLeft = left
-	border.Bottom = middle
-	border.BottomRight = right
-	return border
-}
-
-func chosenView(m model) string {
-	progressPercent := 0.0
-	viewDuration := m.getDurationByIndex(m.ActiveTab)
-
-	if m.ActiveTab == m.ProgressMode {
-		progressPercent = m.ProgressPercent
-		viewDuration = viewDuration - m.ProgressCurrentTime
+	if *importFile != "" {
+		imported, skipped, err := pomodoro.ImportHistory(*importFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "import: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("imported %d records, skipped %d duplicates\n", imported, skipped)
+		return
 	}
 
-	msg := fmt.Sprintf("%s %s", m.ProgressLong.ViewAs(progressPercent), viewDuration.String())
-
-	return msg
-}
-
-func (m model) View() string {
-	doc := strings.Builder{}
-
-	var renderedTabs []string
-
-	for i, t := range m.Tabs {
-		var style lipgloss.Style
-		isFirst, isLast, isActive := i == 0, i == len(m.Tabs)-1, i == m.ActiveTab
-
-		if isActive {
-			style = activeTabStyle.Copy()
-		} else {
-			style = inactiveTabStyle.Copy()
+	if *exportFile != "" {
+		if err := pomodoro.Export(cfg, *exportFile, *exportWeekly, *exportMonthly); err != nil {
+			fmt.Fprintf(os.Stderr, "export: %v\n", err)
+			os.Exit(1)
 		}
+		return
+	}
 
-		border, _, _, _, _ := style.GetBorder()
-
-		if isFirst && isActive {
-			border.BottomLeft = "|"
-		} else if isFirst && !isActive {
-			border.BottomLeft = "├"
-		} else if isLast && isActive {
-			border.BottomRight = "|"
-		} else if isLast && !isActive {
-			border.BottomRight = "┤"
+	if *heatmap {
+		if err := pomodoro.Heatmap(os.Stdout, cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "heatmap: %v\n", err)
+			os.Exit(1)
 		}
+		return
+	}
 
-		style = style.Border(border).Padding(0, 5)
-		if m.ProgressStatus == Running && m.ProgressMode == i {
-			style.Bold(true).Foreground(specialColor)
+	if *review {
+		if err := pomodoro.Review(os.Stdout, cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "review: %v\n", err)
+			os.Exit(1)
 		}
+		return
+	}
 
-		renderedTabs = append(renderedTabs, style.Render(t))
-
+	if *daemon || cfg.Events {
+		if err := pomodoro.RunDaemon(pomodoro.NewModel(cfg, notifier)); err != nil {
+			fmt.Fprintf(os.Stderr, "daemon: %v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	row := lipgloss.JoinHorizontal(lipgloss.Top, renderedTabs...)
-	doc.WriteString(row)
-	doc.WriteString("\n")
-	doc.WriteString(windowStyle.Width((lipgloss.Width(row) - windowStyle.GetHorizontalFrameSize())).Render(chosenView(m)))
-	return docStyle.Render(doc.String())
-}
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		// Redrawing a UI into a pipe or file leaves it full of escape
+		// codes; a transcript reads fine there.
+		cfg.Transcript = true
+		cfg.PauseOnBlur = false
+	}
+	var opts []tea.ProgramOption
+	if !cfg.Transcript {
+		opts = append(opts, tea.WithAltScreen())
+	}
+	m := pomodoro.NewModel(cfg, notifier)
+	if cfg.Start != "" && m.StartMode < 0 {
+		fmt.Fprintf(os.Stderr, "start: no %s session configured\n", cfg.Start)
+		os.Exit(1)
+	}
 
-func main() {
-	p := tea.NewProgram(initialModel(), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	if cfg.PauseOnBlur {
+		fmt.Print(pomodoro.EnableFocusReporting)
+	}
+	p := tea.NewProgram(m, opts...)
+	// SIGHUP reads the config file again into the running app.
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			p.Send(pomodoro.ReloadConfig(flag.CommandLine))
+		}
+	}()
+	final, err := p.Run()
+	signal.Stop(hup)
+	if cfg.PauseOnBlur {
+		fmt.Print(pomodoro.DisableFocusReporting)
+	}
+	if err != nil {
 		fmt.Printf("Alas, there's been an error: %v", err)
 		os.Exit(1)
 	}
+	if cfg.Once.Kind != "" {
+		fmt.Println(final.(pomodoro.Model).OnceSummary())
+	}
+	if m.OneShot && !final.(pomodoro.Model).Completed {
+		os.Exit(pomodoro.ExitAbandoned)
+	}
 }